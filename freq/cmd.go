@@ -0,0 +1,507 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const usage = `usage: freq [options] [file ...]
+
+Print the most common words in the files, or in stdin if there are none ("-"
+is stdin too). Files can be glob patterns ("logs/*.gz"), gzip and bzip2 inputs
+are decompressed.
+
+With -compare, print the words most over-represented in the files compared to
+the reference files, and the most under-represented ones, by log-likelihood.
+Only one side can be stdin.
+
+With -collocations, print the bigrams (pairs of words in a -window) most
+associated by -measure: pointwise mutual information (pmi), t-score (t) or
+log-likelihood (ll).
+
+With -stats, print a JSON report of corpus statistics: number of tokens,
+vocabulary size, type/token ratio, hapax legomena, Zipf and Heaps law fits,
+average word and sentence length.
+
+options:
+`
+
+// errUsage is returned on bad command line, the usage was already printed.
+var errUsage = errors.New("bad usage")
+
+// options are the command line options.
+type options struct {
+	flags         *flag.FlagSet
+	n             int
+	minLen        int
+	caseSensitive bool
+	stopWords     bool
+	pattern       string
+	format        string
+	epsilon       float64
+	workers       int
+	compare       string
+	collocations  bool
+	window        int
+	measure       string
+	minCount      int
+	stats         bool
+}
+
+func newOptions() *options {
+	o := options{flags: flag.NewFlagSet("freq", flag.ContinueOnError)}
+	o.flags.Usage = func() {
+		fmt.Fprint(o.flags.Output(), usage)
+		o.flags.PrintDefaults()
+	}
+	o.flags.IntVar(&o.n, "n", 10, "number of words to print (0 for all)")
+	o.flags.IntVar(&o.minLen, "min", 1, "minimal word length")
+	o.flags.BoolVar(&o.caseSensitive, "case", false, "case sensitive counts")
+	o.flags.BoolVar(&o.stopWords, "stop", false, "drop English stop words")
+	o.flags.StringVar(&o.pattern, "re", wordRe.String(), "regular expression matching words")
+	o.flags.StringVar(&o.format, "format", "table", "output format: table, csv or json")
+	o.flags.Float64Var(&o.epsilon, "epsilon", 0, "approximate counts in constant memory, at most epsilon times the number of words too high (0 for exact counts)")
+	o.flags.IntVar(&o.workers, "workers", 0, "number of counting goroutines (0 for the number of CPUs)")
+	o.flags.StringVar(&o.compare, "compare", "", "compare to reference `files` (comma separated, can be glob patterns)")
+	o.flags.BoolVar(&o.collocations, "collocations", false, "print collocations")
+	o.flags.IntVar(&o.window, "window", 2, "collocation window size, in words (2 for adjacent words)")
+	o.flags.StringVar(&o.measure, "measure", "ll", "collocation measure: pmi, t or ll")
+	o.flags.IntVar(&o.minCount, "min-count", 3, "minimal collocation count")
+	o.flags.BoolVar(&o.stats, "stats", false, "print corpus statistics (as JSON)")
+	return &o
+}
+
+func (o *options) parse(args []string) error {
+	if err := o.flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+
+	var msg string
+	switch {
+	case o.n < 0:
+		msg = fmt.Sprintf("bad -n: %d", o.n)
+	case o.epsilon < 0 || o.epsilon > 1:
+		msg = fmt.Sprintf("bad -epsilon: %v (must be in [0, 1])", o.epsilon)
+	case o.format != "table" && o.format != "csv" && o.format != "json":
+		msg = fmt.Sprintf("unknown format: %q", o.format)
+	case o.compare != "" && o.epsilon > 0:
+		msg = "-compare needs exact counts, can't be used with -epsilon"
+	case o.compare != "" && contains(strings.Split(o.compare, ","), ""):
+		msg = fmt.Sprintf("bad -compare: %q (empty file name)", o.compare)
+	case o.compare != "" && readsStdin(o.flags.Args()) && contains(strings.Split(o.compare, ","), "-"):
+		msg = "stdin can't be both an input and a -compare file"
+	case o.collocations && (o.compare != "" || o.epsilon > 0):
+		msg = "-collocations can't be used with -compare or -epsilon"
+	case o.stats && (o.compare != "" || o.collocations || o.epsilon > 0):
+		msg = "-stats can't be used with -compare, -collocations or -epsilon"
+	case o.window < 2:
+		msg = fmt.Sprintf("bad -window: %d (must be at least 2)", o.window)
+	case measures[o.measure] == nil:
+		msg = fmt.Sprintf("unknown measure: %q", o.measure)
+	default:
+		return nil
+	}
+	fmt.Fprintln(o.flags.Output(), msg)
+	o.flags.Usage()
+	return errUsage
+}
+
+func (o *options) tokenizer() (*tokenizer, error) {
+	re, err := regexp.Compile(o.pattern)
+	if err != nil {
+		return nil, fmt.Errorf("bad -re: %w", err)
+	}
+	t := tokenizer{re: re, minLen: o.minLen, caseSensitive: o.caseSensitive}
+	if o.stopWords {
+		t.stopWords = stopWords
+	}
+	return &t, nil
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	o := newOptions()
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	t, err := o.tokenizer()
+	if err != nil {
+		return err
+	}
+	files, err := expandGlobs(o.flags.Args())
+	if err != nil {
+		return err
+	}
+
+	if o.compare != "" {
+		return o.compareFiles(t, files, stdin, stdout)
+	}
+	if o.collocations {
+		return o.collocateFiles(t, files, stdin, stdout)
+	}
+	if o.stats {
+		c := newStatsCounter(t)
+		if err := eachInput(files, stdin, c.add); err != nil {
+			return err
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(c.report())
+	}
+	if o.epsilon > 0 {
+		var summary *spaceSaving
+		err = eachInput(files, stdin, func(r io.Reader) error {
+			s, err := t.frequencyApprox(r, o.epsilon, o.workers)
+			if err != nil {
+				return err
+			}
+			if summary == nil {
+				summary = s
+				return nil
+			}
+			return summary.merge(s)
+		})
+		if err != nil {
+			return err
+		}
+		top := summary.top(o.topN(len(summary.counters)))
+		return writeFreqs(stdout, o.format, top, summary.n)
+	}
+
+	freqs, err := o.count(t, files, stdin)
+	if err != nil {
+		return err
+	}
+	top := maxNWords(freqs, o.topN(len(freqs)))
+	return writeFreqs(stdout, o.format, top, sumCounts(freqs))
+}
+
+// count returns the count of every word in files.
+func (o *options) count(t *tokenizer, files []string, stdin io.Reader) (map[string]int, error) {
+	freqs := make(map[string]int) // word -> count
+	err := eachInput(files, stdin, func(r io.Reader) error {
+		ffreqs, err := t.frequency(r, o.workers)
+		if err != nil {
+			return err
+		}
+		for w, n := range ffreqs {
+			freqs[w] += n
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return freqs, nil
+}
+
+// compareFiles writes the keyness of words in files compared to the -compare
+// files.
+func (o *options) compareFiles(t *tokenizer, files []string, stdin io.Reader, stdout io.Writer) error {
+	refFiles, err := expandGlobs(strings.Split(o.compare, ","))
+	if err != nil {
+		return err
+	}
+	freqs, err := o.count(t, files, stdin)
+	if err != nil {
+		return err
+	}
+	ref, err := o.count(t, refFiles, stdin)
+	if err != nil {
+		return err
+	}
+
+	n := o.n
+	if n == 0 {
+		n = len(freqs) + len(ref)
+	}
+	over, under := compareFrequencies(freqs, ref, n)
+	return writeKeyness(stdout, o.format, over, under, sumCounts(freqs), sumCounts(ref))
+}
+
+// collocateFiles writes the collocations in files.
+func (o *options) collocateFiles(t *tokenizer, files []string, stdin io.Reader, stdout io.Writer) error {
+	var total *cooccurrences
+	err := eachInput(files, stdin, func(r io.Reader) error {
+		c, err := t.cooccurrences(r, o.window)
+		if err != nil {
+			return err
+		}
+		if total == nil {
+			total = c
+			return nil
+		}
+		total.merge(c)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	n := o.n
+	if n == 0 {
+		n = len(total.pairs)
+	}
+	cols, err := total.collocations(o.measure, o.minCount, n)
+	if err != nil {
+		return err
+	}
+	return writeCollocations(stdout, o.format, cols)
+}
+
+// topN returns the number of words to print out of size.
+func (o *options) topN(size int) int {
+	if o.n == 0 || o.n > size {
+		return size
+	}
+	return o.n
+}
+
+// expandGlobs returns files with glob patterns replaced by the matching files.
+func expandGlobs(files []string) ([]string, error) {
+	var names []string
+	for _, pattern := range files {
+		if pattern == "-" || !strings.ContainsAny(pattern, `*?[\`) {
+			names = append(names, pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%q: no matching files", pattern)
+		}
+		names = append(names, matches...)
+	}
+	return names, nil
+}
+
+// readsStdin reports if eachInput reads stdin for files.
+func readsStdin(files []string) bool {
+	return len(files) == 0 || contains(files, "-")
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// eachInput calls fn with every (decompressed) input file, or with stdin if
+// there are no files.
+func eachInput(files []string, stdin io.Reader, fn func(r io.Reader) error) error {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	for _, name := range files {
+		err := func() error {
+			var r io.Reader = stdin
+			if name != "-" {
+				file, err := os.Open(name)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				r = file
+			}
+
+			r, err := decompress(r)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if err := fn(r); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return nil
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+)
+
+// decompress returns a reader decompressing r if it's gzip or bzip2 data
+// (detected by their magic bytes), r otherwise.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(len(bzip2Magic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(header, bzip2Magic):
+		return bzip2.NewReader(br), nil
+	}
+	return br, nil
+}
+
+// writeRecords writes records (a slice) as JSON, or header and rows as CSV or
+// as a table.
+func writeRecords(w io.Writer, format string, records any, header []string, rows [][]string) error {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case "json":
+		if err := json.NewEncoder(bw).Encode(records); err != nil {
+			return err
+		}
+	case "csv":
+		cw := csv.NewWriter(bw)
+		cw.Write(header)
+		cw.WriteAll(rows) // flushes
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	default:
+		tw := tabwriter.NewWriter(bw, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// percent returns count as a percentage of total.
+func percent(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(count) / float64(total)
+}
+
+// freqRecord is an output record of word counts.
+type freqRecord struct {
+	Word    string  `json:"word"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"` // of all words
+}
+
+func writeFreqs(w io.Writer, format string, top []freqStruct, total int) error {
+	records := make([]freqRecord, len(top))
+	rows := make([][]string, len(top))
+	for i, f := range top {
+		r := freqRecord{Word: f.word, Count: f.count, Percent: percent(f.count, total)}
+		records[i] = r
+		rows[i] = []string{r.Word, strconv.Itoa(r.Count), formatPercent(format, r.Percent)}
+	}
+	return writeRecords(w, format, records, []string{"word", "count", "percent"}, rows)
+}
+
+func formatPercent(format string, p float64) string {
+	s := strconv.FormatFloat(p, 'f', 2, 64)
+	if format == "table" {
+		s += "%"
+	}
+	return s
+}
+
+// keynessRecord is an output record of word keyness.
+type keynessRecord struct {
+	Side          string  `json:"side"` // "over" or "under" represented
+	Word          string  `json:"word"`
+	Count         int     `json:"count"`
+	Percent       float64 `json:"percent"`
+	RefCount      int     `json:"ref_count"`
+	RefPercent    float64 `json:"ref_percent"`
+	LogLikelihood float64 `json:"log_likelihood"`
+	ChiSquare     float64 `json:"chi_square"`
+}
+
+func writeKeyness(w io.Writer, format string, over, under []keyness, total, refTotal int) error {
+	records := make([]keynessRecord, 0, len(over)+len(under))
+	rows := make([][]string, 0, len(over)+len(under))
+	add := func(side string, ks []keyness) {
+		for _, k := range ks {
+			r := keynessRecord{
+				Side:          side,
+				Word:          k.word,
+				Count:         k.count,
+				Percent:       percent(k.count, total),
+				RefCount:      k.refCount,
+				RefPercent:    percent(k.refCount, refTotal),
+				LogLikelihood: k.logLikelihood,
+				ChiSquare:     k.chiSquare,
+			}
+			records = append(records, r)
+			rows = append(rows, []string{
+				r.Side, r.Word,
+				strconv.Itoa(r.Count), formatPercent(format, r.Percent),
+				strconv.Itoa(r.RefCount), formatPercent(format, r.RefPercent),
+				strconv.FormatFloat(r.LogLikelihood, 'f', 2, 64),
+				strconv.FormatFloat(r.ChiSquare, 'f', 2, 64),
+			})
+		}
+	}
+	add("over", over)
+	add("under", under)
+
+	header := []string{"side", "word", "count", "percent", "ref_count", "ref_percent", "log_likelihood", "chi_square"}
+	return writeRecords(w, format, records, header, rows)
+}
+
+// collocationRecord is an output record of collocations.
+type collocationRecord struct {
+	First         string  `json:"first"`
+	Second        string  `json:"second"`
+	Count         int     `json:"count"`
+	PMI           float64 `json:"pmi"`
+	TScore        float64 `json:"t_score"`
+	LogLikelihood float64 `json:"log_likelihood"`
+}
+
+func writeCollocations(w io.Writer, format string, cols []collocation) error {
+	records := make([]collocationRecord, len(cols))
+	rows := make([][]string, len(cols))
+	for i, c := range cols {
+		r := collocationRecord{
+			First:         c.first,
+			Second:        c.second,
+			Count:         c.count,
+			PMI:           c.pmi,
+			TScore:        c.tScore,
+			LogLikelihood: c.logLikelihood,
+		}
+		records[i] = r
+		rows[i] = []string{
+			r.First, r.Second, strconv.Itoa(r.Count),
+			strconv.FormatFloat(r.PMI, 'f', 2, 64),
+			strconv.FormatFloat(r.TScore, 'f', 2, 64),
+			strconv.FormatFloat(r.LogLikelihood, 'f', 2, 64),
+		}
+	}
+	header := []string{"first", "second", "count", "pmi", "t_score", "log_likelihood"}
+	return writeRecords(w, format, records, header, rows)
+}
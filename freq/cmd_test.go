@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runCmd(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := run(args, strings.NewReader(stdin), &out); err != nil {
+		t.Fatalf("%v: %s", args, err)
+	}
+	return out.String()
+}
+
+func TestRun(t *testing.T) {
+	text := "The cat and the hat.\nA CAT sat on the mat.\n"
+	cases := []struct {
+		stdin    string
+		args     []string
+		expected string
+	}{
+		{
+			text,
+			[]string{"-n", "2"},
+			"word  count  percent\nthe   3      27.27%\ncat   2      18.18%\n",
+		},
+		{
+			text,
+			[]string{"-n", "2", "-case", "-format", "csv"},
+			"word,count,percent\nthe,2,18.18\nA,1,9.09\n",
+		},
+		{
+			text,
+			[]string{"-n", "1", "-stop", "-min", "3", "-format", "json"},
+			`[{"word":"cat","count":2,"percent":40}]` + "\n",
+		},
+		{
+			text,
+			[]string{"-n", "0", "-re", `[a-z]*at`, "-format", "csv"},
+			"word,count,percent\ncat,1,25.00\nhat,1,25.00\nmat,1,25.00\nsat,1,25.00\n",
+		},
+		{
+			text,
+			[]string{"-n", "2", "-epsilon", "0.1", "-format", "csv"},
+			"word,count,percent\nthe,3,27.27\ncat,2,18.18\n",
+		},
+		{
+			"",
+			[]string{"-format", "json"},
+			"[]\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			if out := runCmd(t, tc.stdin, tc.args...); out != tc.expected {
+				t.Fatalf("expected:\n%s\ngot:\n%s", tc.expected, out)
+			}
+		})
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte("b b c"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	write("a.txt", []byte("a b"))
+	write("b.txt.gz", gz.Bytes())
+
+	out := runCmd(t, "c", "-format", "csv", filepath.Join(dir, "*"), "-")
+	expected := "word,count,percent\nb,3,50.00\nc,2,33.33\na,1,16.67\n"
+	if out != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+
+	// there's no bzip2 writer in the standard library
+	out = runCmd(t, "", "-format", "csv", filepath.Join("testdata", "d.txt.bz2"))
+	if expected := "word,count,percent\nd,2,100.00\n"; out != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	cases := [][]string{
+		{"-n", "-1"},
+		{"-format", "xml"},
+		{"-epsilon", "2"},
+		{"-re", "("},
+		{"no-such-file.txt"},
+		{"no-such-dir/*.txt"},
+	}
+	for _, args := range cases {
+		var out bytes.Buffer
+		err := run(args, strings.NewReader(""), &out)
+		if err == nil {
+			t.Fatalf("%v: expected error", args)
+		}
+		if strings.HasPrefix(args[0], "-") && args[0] != "-re" && !errors.Is(err, errUsage) {
+			t.Fatalf("%v: expected usage error, got %s", args, err)
+		}
+	}
+}
+
+func TestRunCompare(t *testing.T) {
+	dir := t.TempDir()
+	ref := filepath.Join(dir, "ref.txt")
+	if err := os.WriteFile(ref, []byte("a a a a b b b b c c"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runCmd(t, "a a c c c", "-compare", ref, "-n", "1", "-format", "csv")
+	expected := `side,word,count,percent,ref_count,ref_percent,log_likelihood,chi_square
+over,c,3,60.00,2,20.00,1.48,2.40
+under,b,0,0.00,4,40.00,3.24,2.73
+`
+	if out != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+
+	var stderr bytes.Buffer
+	err := run([]string{"-compare", ref, "-epsilon", "0.1"}, strings.NewReader(""), &stderr)
+	if !errors.Is(err, errUsage) {
+		t.Fatalf("-epsilon: expected usage error, got %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"-compare", "-"},
+		{"-compare", ref + ",-"},
+		{"-compare", "-", "-"},
+		{"-compare", "-", ref, "-"},
+		{"-compare", ref + ","},
+		{"-compare", "," + ref, ref},
+	} {
+		stderr.Reset()
+		err := run(args, strings.NewReader("a b"), &stderr)
+		if !errors.Is(err, errUsage) {
+			t.Fatalf("%v: expected usage error, got %v", args, err)
+		}
+	}
+
+	// stdin is fine on one side
+	out = runCmd(t, "a a c c c", "-compare", "-", "-n", "1", "-format", "csv", ref)
+	expected = `side,word,count,percent,ref_count,ref_percent,log_likelihood,chi_square
+over,b,4,40.00,0,0.00,3.24,2.73
+under,c,2,20.00,3,60.00,1.48,2.40
+`
+	if out != expected {
+		t.Fatalf("-compare -: expected:\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestRunCollocations(t *testing.T) {
+	text := "Baker Street. Holmes at Baker Street.\n\nStreet Baker and Baker Street."
+	out := runCmd(t, text, "-collocations", "-min-count", "2", "-format", "csv")
+	expected := "first,second,count,pmi,t_score,log_likelihood\nbaker,street,3,1.17,0.96,6.96\n"
+	if out != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+
+	for _, args := range [][]string{
+		{"-collocations", "-epsilon", "0.1"},
+		{"-collocations", "-window", "1"},
+		{"-collocations", "-measure", "dice"},
+	} {
+		err := run(args, strings.NewReader(""), io.Discard)
+		if !errors.Is(err, errUsage) {
+			t.Fatalf("%v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestRunStats(t *testing.T) {
+	out := runCmd(t, "Who's on first? Who's on second.", "-stats")
+	var s corpusStats
+	if err := json.Unmarshal([]byte(out), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Tokens != 8 || s.Vocabulary != 5 || s.Sentences != 2 || len(s.Heaps.Curve) == 0 {
+		t.Fatalf("bad report: %s", out)
+	}
+
+	err := run([]string{"-stats", "-collocations"}, strings.NewReader(""), io.Discard)
+	if !errors.Is(err, errUsage) {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// bigram is a pair of words, first appearing before second.
+type bigram struct {
+	first, second string
+}
+
+// cooccurrences are counts of bigrams within a window of words.
+type cooccurrences struct {
+	window  int
+	pairs   map[bigram]int
+	firsts  map[string]int // word -> number of pairs it starts
+	seconds map[string]int // word -> number of pairs it ends
+	total   int            // number of pairs
+}
+
+// cooccurrences counts the bigrams in r of words at most window-1 words apart
+// (window = 2 counts adjacent words). Windows span lines but not paragraphs
+// (empty lines).
+func (t *tokenizer) cooccurrences(r io.Reader, window int) (*cooccurrences, error) {
+	if window < 2 {
+		return nil, fmt.Errorf("window must be at least 2, got %d", window)
+	}
+	c := cooccurrences{
+		window:  window,
+		pairs:   make(map[bigram]int),
+		firsts:  make(map[string]int),
+		seconds: make(map[string]int),
+	}
+
+	prev := make([]string, 0, window-1) // previous words in window
+	add := func(word string) {
+		for _, p := range prev {
+			c.pairs[bigram{p, word}]++
+			c.firsts[p]++
+			c.seconds[word]++
+			c.total++
+		}
+		if len(prev) == window-1 {
+			copy(prev, prev[1:])
+			prev = prev[:len(prev)-1]
+		}
+		prev = append(prev, word)
+	}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if strings.TrimSpace(line) == "" {
+			prev = prev[:0] // paragraph break
+		} else {
+			t.words(line, add)
+		}
+		if errors.Is(err, io.EOF) {
+			return &c, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// merge adds the counts of other, which must have the same window, to c.
+func (c *cooccurrences) merge(other *cooccurrences) {
+	for b, n := range other.pairs {
+		c.pairs[b] += n
+	}
+	for w, n := range other.firsts {
+		c.firsts[w] += n
+	}
+	for w, n := range other.seconds {
+		c.seconds[w] += n
+	}
+	c.total += other.total
+}
+
+// collocation is a bigram with its association scores.
+type collocation struct {
+	bigram
+	count         int
+	pmi           float64 // pointwise mutual information, in bits
+	tScore        float64
+	logLikelihood float64 // G² (Dunning 1993)
+}
+
+// collocation measures, by name.
+var measures = map[string]func(c collocation) float64{
+	"pmi": func(c collocation) float64 { return c.pmi },
+	"t":   func(c collocation) float64 { return c.tScore },
+	"ll":  func(c collocation) float64 { return c.logLikelihood },
+}
+
+// score returns the scores of the bigram b.
+func (c *cooccurrences) score(b bigram) collocation {
+	n, first, second := c.pairs[b], c.firsts[b.first], c.seconds[b.second]
+	expected := float64(first) * float64(second) / float64(c.total)
+	col := collocation{bigram: b, count: n}
+	if n == 0 {
+		return col
+	}
+	col.pmi = math.Log2(float64(n) / expected)
+	col.tScore = (float64(n) - expected) / math.Sqrt(float64(n))
+	col.logLikelihood = gSquare(n, first-n, second-n, c.total-first-second+n)
+	return col
+}
+
+// collocations returns the n bigrams seen at least minCount times with the
+// highest measure (see measures), ties broken by count then words.
+func (c *cooccurrences) collocations(measure string, minCount, n int) ([]collocation, error) {
+	value, ok := measures[measure]
+	if !ok {
+		return nil, fmt.Errorf("unknown measure: %q", measure)
+	}
+
+	var cols []collocation
+	for b, count := range c.pairs {
+		if count >= minCount {
+			cols = append(cols, c.score(b))
+		}
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		a, b := cols[i], cols[j]
+		if va, vb := value(a), value(b); va != vb {
+			return va > vb
+		}
+		if a.count != b.count {
+			return a.count > b.count
+		}
+		if a.first != b.first {
+			return a.first < b.first
+		}
+		return a.second < b.second
+	})
+	if len(cols) > n {
+		cols = cols[:n]
+	}
+	return cols, nil
+}
+
+// gSquare returns the G² statistic of the 2x2 contingency table
+// [[k11, k12], [k21, k22]].
+func gSquare(k11, k12, k21, k22 int) float64 {
+	n := float64(k11 + k12 + k21 + k22)
+	rows := [2]float64{float64(k11 + k12), float64(k21 + k22)}
+	cols := [2]float64{float64(k11 + k21), float64(k12 + k22)}
+	table := [2][2]int{{k11, k12}, {k21, k22}}
+
+	g := 0.0
+	for i, row := range table {
+		for j, k := range row {
+			g += xLogRatio(k, rows[i]*cols[j]/n)
+		}
+	}
+	return 2 * g
+}
@@ -2,54 +2,34 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"container/heap"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"regexp"
-	"sort"
 	"strings"
+	"sync"
 )
 
 // Q: What is the most common word in sherlock.txt?
 // word Frequency
 
 func main() {
-	file, err := os.Open("sherlock.txt")
-	if err != nil {
-		log.Fatalf("error: %s", err)
-	}
-	defer file.Close()
-
-	// w, err := mostCommon(file)
-	// if err != nil {
-	// 	log.Fatalf("error: %s", err)
-	// }
-	// fmt.Println(w)
-
-	ws, err := mostCommonN(file, 10)
-	if err != nil {
+	log.SetFlags(0)
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
 		log.Fatalf("error: %s", err)
 	}
-	fmt.Println(ws)
 
 	// mapDemo()
-
-	// path := "C:\to\new\report.csv"
-	// `s` is a "raw" string, \ is just a \
-	path := `C:\to\new\report.csv`
-	fmt.Println(path)
-}
-
-func mostCommonN(r io.Reader, n int) ([]string, error) {
-	freqs, err := wordFrequency(r)
-	if err != nil {
-		return []string{}, err
-	}
-	if len(freqs) < n {
-		return []string{}, fmt.Errorf("only %d distinct words, asked %d", len(freqs), n)
-	}
-	return maxNWords(freqs, n)
 }
 
 type freqStruct struct {
@@ -57,23 +37,55 @@ type freqStruct struct {
 	word  string
 }
 
-func maxNWords(freqs map[string]int, n int) ([]string, error) {
-	freqSlice := make([]freqStruct, 0)
-	for k, v := range freqs {
-		freqSlice = append(freqSlice, freqStruct{count: v, word: k})
+// before reports if a comes before b in the top words: higher count first,
+// ties are broken by word.
+func (a freqStruct) before(b freqStruct) bool {
+	if a.count != b.count {
+		return a.count > b.count
 	}
+	return a.word < b.word
+}
 
-	sort.Slice(freqSlice, func(i, j int) bool {
-		return freqSlice[i].count > freqSlice[j].count
-	})
+// freqHeap is a min-heap of words, the root is the last of the top words.
+type freqHeap []freqStruct
 
-	// sort.Sort(freqSlice)
+func (h freqHeap) Len() int           { return len(h) }
+func (h freqHeap) Less(i, j int) bool { return h[j].before(h[i]) }
+func (h freqHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *freqHeap) Push(x any)        { *h = append(*h, x.(freqStruct)) }
+
+func (h *freqHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
 
-	w := make([]string, 0)
-	for _, fs := range freqSlice[:n+1] {
-		w = append(w, fs.word)
+// maxNWords returns the n most common words (or all of them if there are
+// fewer), most common first. It keeps the top n in a heap, which is
+// O(V log n) for V words.
+func maxNWords(freqs map[string]int, n int) []freqStruct {
+	if n <= 0 {
+		return nil
 	}
-	return w, nil
+
+	h := make(freqHeap, 0, n)
+	for word, count := range freqs {
+		fs := freqStruct{count: count, word: word}
+		switch {
+		case len(h) < n:
+			heap.Push(&h, fs)
+		case fs.before(h[0]):
+			h[0] = fs
+			heap.Fix(&h, 0)
+		}
+	}
+
+	top := make([]freqStruct, len(h))
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i] = heap.Pop(&h).(freqStruct)
+	}
+	return top
 }
 
 func mostCommon(r io.Reader) (string, error) {
@@ -180,3 +192,59 @@ func wordFrequency(r io.Reader) (map[string]int, error) {
 
 	return freqs, nil
 }
+
+// chunkSize is the approximate size of chunks counted by each worker in
+// countChunks.
+const chunkSize = 1 << 20
+
+// countChunks splits r to chunks (see splitChunks) and calls count with every
+// chunk from one of workers goroutines. count gets the index of its worker,
+// countChunks returns once all the chunks are counted.
+func countChunks(r io.Reader, workers int, count func(worker int, chunk string)) error {
+	chunks := make(chan []byte, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for chunk := range chunks {
+				count(worker, string(chunk))
+			}
+		}(i)
+	}
+
+	err := splitChunks(r, chunks)
+	close(chunks)
+	wg.Wait()
+	return err
+}
+
+// splitChunks reads r and sends chunks of about chunkSize bytes, ending at a
+// line boundary, to chunks.
+func splitChunks(r io.Reader, chunks chan<- []byte) error {
+	br := bufio.NewReaderSize(r, chunkSize)
+	for {
+		chunk := make([]byte, chunkSize)
+		n, err := io.ReadFull(br, chunk)
+		chunk = chunk[:n]
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			if n > 0 {
+				chunks <- chunk
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// complete the last line
+		if !bytes.HasSuffix(chunk, []byte("\n")) {
+			rest, err := br.ReadBytes('\n')
+			chunk = append(chunk, rest...)
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+		}
+		chunks <- chunk
+	}
+}
@@ -0,0 +1,553 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func loadSherlock(tb testing.TB) []byte {
+	data, err := os.ReadFile("sherlock.txt")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return data
+}
+
+// defaultTokenizer splits words like wordFrequency.
+var defaultTokenizer = &tokenizer{re: wordRe}
+
+func TestTokenizerFrequency(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	sherlock := loadSherlock(t)
+	texts := map[string][]byte{
+		"empty":       nil,
+		"no newline":  []byte("Who's on first? who"),
+		"sherlock":    sherlock,
+		"sherlock x5": bytes.Repeat(sherlock, 5), // several chunks
+		"long lines":  []byte(strings.Repeat("a b ", chunkSize) + "\nc\n"),
+	}
+	for name, text := range texts {
+		t.Run(name, func(t *testing.T) {
+			var expected map[string]int
+			if name == "long lines" { // too long for bufio.Scanner
+				expected = map[string]int{"a": chunkSize, "b": chunkSize, "c": 1}
+			} else {
+				var err error
+				expected, err = wordFrequency(bytes.NewReader(text))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, workers := range []int{0, 1, 3} {
+				freqs, err := defaultTokenizer.frequency(bytes.NewReader(text), workers)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(expected, freqs) {
+					t.Fatalf("workers=%d: results differ from wordFrequency", workers)
+				}
+			}
+
+			// short reads
+			freqs, err := defaultTokenizer.frequency(iotest.HalfReader(bytes.NewReader(text)), 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expected, freqs) {
+				t.Fatal("half reader: results differ from wordFrequency")
+			}
+		})
+	}
+}
+
+func TestTokenizerFrequencyError(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("a b c\n"), iotest.ErrReader(io.ErrClosedPipe))
+	if _, err := defaultTokenizer.frequency(r, 2); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestStopWordsCopy(t *testing.T) {
+	data, err := os.ReadFile("../nlp/stopwords/en.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != stopWordsData {
+		t.Fatal("stopwords.txt differs from nlp/stopwords/en.txt, run go generate")
+	}
+}
+
+// corpus is sherlock.txt repeated many times.
+func corpus(b *testing.B) []byte {
+	return bytes.Repeat(loadSherlock(b), 20)
+}
+
+func BenchmarkWordFrequency(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	data := corpus(b)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := wordFrequency(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTokenizerFrequency(b *testing.B) {
+	data := corpus(b)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := defaultTokenizer.frequency(bytes.NewReader(data), 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMaxNWords(t *testing.T) {
+	freqs := map[string]int{"b": 2, "a": 2, "c": 2, "d": 5, "e": 1}
+	cases := []struct {
+		n        int
+		expected []freqStruct
+	}{
+		{0, nil},
+		{1, []freqStruct{{5, "d"}}},
+		{3, []freqStruct{{5, "d"}, {2, "a"}, {2, "b"}}},
+		{5, []freqStruct{{5, "d"}, {2, "a"}, {2, "b"}, {2, "c"}, {1, "e"}}},
+		{10, []freqStruct{{5, "d"}, {2, "a"}, {2, "b"}, {2, "c"}, {1, "e"}}},
+	}
+	for _, tc := range cases {
+		for i := 0; i < 10; i++ { // map order is random
+			top := maxNWords(freqs, tc.n)
+			if !reflect.DeepEqual(tc.expected, top) {
+				t.Fatalf("n=%d: expected %v, got %v", tc.n, tc.expected, top)
+			}
+		}
+	}
+}
+
+func TestMaxNWordsSherlock(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	sherlock := loadSherlock(t)
+	freqs, err := wordFrequency(bytes.NewReader(sherlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := make([]freqStruct, 0, len(freqs))
+	for word, count := range freqs {
+		all = append(all, freqStruct{count, word})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].before(all[j]) })
+
+	for _, n := range []int{1, 10, 100, len(freqs)} {
+		top := maxNWords(freqs, n)
+		if !reflect.DeepEqual(all[:n], top) {
+			t.Fatalf("n=%d: top words differ from sorted words", n)
+		}
+	}
+}
+
+func TestSpaceSaving(t *testing.T) {
+	s, err := newSpaceSaving(0.5) // 2 counters
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range strings.Fields("a a b c c c") {
+		s.add(w)
+	}
+	// "c" replaced "b" (count 1) and inherited its count
+	expected := []freqStruct{{4, "c"}, {2, "a"}}
+	if top := s.top(2); !reflect.DeepEqual(expected, top) {
+		t.Fatalf("expected %v, got %v", expected, top)
+	}
+	if c := s.counters["c"]; c.err != 1 {
+		t.Fatalf("expected error 1 for %q, got %d", c.word, c.err)
+	}
+
+	for _, eps := range []float64{0, -1, 1.5} {
+		if _, err := newSpaceSaving(eps); err == nil {
+			t.Fatalf("epsilon=%v: expected error", eps)
+		}
+	}
+}
+
+// checkSpaceSaving checks the heap of s and its counts against exact counts.
+func checkSpaceSaving(t *testing.T, s *spaceSaving, exact map[string]int, epsilon float64) {
+	t.Helper()
+	for i, c := range s.heap {
+		if c.index != i {
+			t.Fatalf("%q: index %d at %d", c.word, c.index, i)
+		}
+		if i > 0 && s.heap.Less(i, (i-1)/2) {
+			t.Fatalf("%q: heap invariant broken at %d", c.word, i)
+		}
+		if s.counters[c.word] != c {
+			t.Fatalf("%q: not in counters", c.word)
+		}
+	}
+	if len(s.counters) != len(s.heap) {
+		t.Fatalf("%d counters, %d in heap", len(s.counters), len(s.heap))
+	}
+
+	bound := int(epsilon * float64(s.n))
+	for word, c := range s.counters {
+		if c.count < exact[word] || c.count-c.err > exact[word] || c.err > bound {
+			t.Fatalf("%q: count %d (error %d) out of bounds for %d", word, c.count, c.err, exact[word])
+		}
+	}
+}
+
+func TestSpaceSavingMerge(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	const eps = 0.001
+	sherlock := loadSherlock(t)
+	freqs, err := wordFrequency(bytes.NewReader(sherlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, n := range freqs {
+		total += n
+	}
+
+	// merge summaries of the two halves of the text
+	lines := strings.SplitAfter(string(sherlock), "\n")
+	half := strings.Join(lines[:len(lines)/2], "")
+	merged, err := defaultTokenizer.frequencyApprox(strings.NewReader(half), eps, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest, err := defaultTokenizer.frequencyApprox(strings.NewReader(string(sherlock[len(half):])), eps, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := merged.merge(rest); err != nil {
+		t.Fatal(err)
+	}
+
+	if merged.n != total {
+		t.Fatalf("expected %d words, got %d", total, merged.n)
+	}
+	if len(merged.counters) != merged.k || len(merged.heap) != merged.k {
+		t.Fatalf("expected %d counters, got %d", merged.k, len(merged.counters))
+	}
+	checkSpaceSaving(t, merged, freqs, eps)
+	bound := int(eps * float64(total))
+	for word, n := range freqs {
+		if _, ok := merged.counters[word]; n > bound && !ok {
+			t.Fatalf("%q: missing frequent word (%d)", word, n)
+		}
+	}
+
+	// adds after merges keep the heap and the error bound
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		exact := make(map[string]int)
+		summaries := make([]*spaceSaving, 2)
+		for j := range summaries {
+			summaries[j], _ = newSpaceSaving(0.1)
+			for k := 0; k < 50; k++ {
+				word := strconv.Itoa(int(rnd.ExpFloat64() * 5))
+				summaries[j].add(word)
+				exact[word]++
+			}
+		}
+		s := summaries[0]
+		if err := s.merge(summaries[1]); err != nil {
+			t.Fatal(err)
+		}
+		for k := 0; k < 50; k++ {
+			word := strconv.Itoa(int(rnd.ExpFloat64() * 5))
+			s.add(word)
+			exact[word]++
+		}
+		checkSpaceSaving(t, s, exact, 0.1)
+	}
+
+	other, _ := newSpaceSaving(0.01)
+	if err := merged.merge(other); err == nil {
+		t.Fatal("expected error merging different sizes")
+	}
+}
+
+func TestTokenizerFrequencyApprox(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	const eps = 0.001
+	sherlock := loadSherlock(t)
+	text := bytes.Repeat(sherlock, 3) // several chunks
+	freqs, err := wordFrequency(bytes.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, n := range freqs {
+		total += n
+	}
+
+	const n = 20
+	exact := maxNWords(freqs, n)
+	for _, workers := range []int{1, 4} {
+		s, err := defaultTokenizer.frequencyApprox(bytes.NewReader(text), eps, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		top := s.top(n)
+		for i, f := range top {
+			if f.word != exact[i].word {
+				t.Fatalf("workers=%d: #%d expected %q, got %q", workers, i, exact[i].word, f.word)
+			}
+			if f.count < freqs[f.word] || float64(f.count-freqs[f.word]) > eps*float64(total) {
+				t.Fatalf("workers=%d: %q count %d too far from %d", workers, f.word, f.count, freqs[f.word])
+			}
+		}
+	}
+
+	if _, err := defaultTokenizer.frequencyApprox(bytes.NewReader(text), 0, 1); err == nil {
+		t.Fatal("expected error for bad epsilon")
+	}
+}
+
+func BenchmarkTokenizerFrequencyApprox(b *testing.B) {
+	data := corpus(b)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := defaultTokenizer.frequencyApprox(bytes.NewReader(data), 0.001, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestKeynessScores(t *testing.T) {
+	const eps = 1e-9
+	if ll := logLikelihood(10, 20, 1000, 10000); math.Abs(ll-13.579462550451632) > eps {
+		t.Fatalf("log-likelihood: got %v", ll)
+	}
+	if chi2 := chiSquare(10, 20, 1000, 10000); math.Abs(chi2-21.3916742631419) > eps {
+		t.Fatalf("chi-square: got %v", chi2)
+	}
+	// same relative frequency
+	if ll, chi2 := logLikelihood(1, 10, 100, 1000), chiSquare(1, 10, 100, 1000); ll > eps || chi2 > eps {
+		t.Fatalf("expected 0, got %v, %v", ll, chi2)
+	}
+	if ll, chi2 := logLikelihood(0, 0, 0, 0), chiSquare(0, 0, 0, 0); ll != 0 || chi2 != 0 {
+		t.Fatalf("empty: expected 0, got %v, %v", ll, chi2)
+	}
+}
+
+func TestCompareFrequencies(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// "A Scandal in Bohemia", the first story, compared to the collection
+	sherlock := loadSherlock(t)
+	lines := strings.SplitAfter(string(sherlock), "\n")
+	story := strings.Join(lines[50:1130], "")
+	freqs, err := wordFrequency(strings.NewReader(story))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := wordFrequency(bytes.NewReader(sherlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	over, under := compareFrequencies(freqs, ref, 5)
+	words := func(ks []keyness) []string {
+		var words []string
+		for _, k := range ks {
+			words = append(words, k.word)
+		}
+		return words
+	}
+	if expected := []string{"photograph", "majesty", "king", "irene", "adler"}; !reflect.DeepEqual(expected, words(over)) {
+		t.Fatalf("over: expected %v, got %v", expected, words(over))
+	}
+	if expected := []string{"gutenberg", "project", "father", "that", "mr"}; !reflect.DeepEqual(expected, words(under)) {
+		t.Fatalf("under: expected %v, got %v", expected, words(under))
+	}
+	if k := over[0]; k.count != 21 || k.refCount != 25 || k.chiSquare <= 0 {
+		t.Fatalf("bad keyness: %+v", k)
+	}
+
+	over, under = compareFrequencies(freqs, freqs, 5)
+	if len(over) != 0 || len(under) != 0 {
+		t.Fatalf("same corpus: expected no keywords, got %v, %v", over, under)
+	}
+}
+
+func TestCooccurrences(t *testing.T) {
+	text := "a b c\nb c\n\nc a\n"
+	c, err := defaultTokenizer.cooccurrences(strings.NewReader(text), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[bigram]int{
+		{"a", "b"}: 1, {"a", "c"}: 1, {"b", "c"}: 2, {"b", "b"}: 1, {"c", "b"}: 1, {"c", "c"}: 1, // first paragraph
+		{"c", "a"}: 1,
+	}
+	if !reflect.DeepEqual(expected, c.pairs) {
+		t.Fatalf("expected %v, got %v", expected, c.pairs)
+	}
+	if c.total != 8 || c.firsts["b"] != 3 || c.seconds["c"] != 4 {
+		t.Fatalf("bad marginals: %d %v %v", c.total, c.firsts, c.seconds)
+	}
+
+	other, err := defaultTokenizer.cooccurrences(strings.NewReader("b c"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.merge(other)
+	if c.pairs[bigram{"b", "c"}] != 3 || c.total != 9 {
+		t.Fatalf("bad merge: %v", c.pairs)
+	}
+
+	if _, err := defaultTokenizer.cooccurrences(strings.NewReader(text), 1); err == nil {
+		t.Fatal("expected error for window < 2")
+	}
+}
+
+func TestGSquare(t *testing.T) {
+	if g := gSquare(10, 20, 30, 940); math.Abs(g-30.06907506178831) > 1e-9 {
+		t.Fatalf("got %v", g)
+	}
+	if g := gSquare(1, 9, 10, 90); math.Abs(g) > 1e-9 { // independent
+		t.Fatalf("independent: got %v", g)
+	}
+}
+
+func TestCollocations(t *testing.T) {
+	tok := &tokenizer{re: wordRe, stopWords: stopWords}
+	c, err := tok.cooccurrences(bytes.NewReader(loadSherlock(t)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, measure := range []string{"pmi", "t", "ll"} {
+		cols, err := c.collocations(measure, 20, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := make(map[bigram]bool)
+		for i, col := range cols {
+			if col.count < 20 {
+				t.Fatalf("%s: %v seen %d times", measure, col.bigram, col.count)
+			}
+			if i > 0 && measures[measure](col) > measures[measure](cols[i-1]) {
+				t.Fatalf("%s: not sorted", measure)
+			}
+			found[col.bigram] = true
+		}
+		for _, b := range []bigram{{"sherlock", "holmes"}, {"baker", "street"}} {
+			if !found[b] {
+				t.Fatalf("%s: %v not in %v", measure, b, cols)
+			}
+		}
+	}
+
+	if _, err := c.collocations("dice", 1, 10); err == nil {
+		t.Fatal("expected error for unknown measure")
+	}
+}
+
+func TestLinearFit(t *testing.T) {
+	const eps = 1e-9
+	f := linearFit([]float64{1, 2, 3}, []float64{3, 5, 7})
+	if math.Abs(f.Slope-2) > eps || math.Abs(f.Intercept-1) > eps || math.Abs(f.R2-1) > eps {
+		t.Fatalf("exact line: got %+v", f)
+	}
+	f = linearFit([]float64{1, 2, 3, 4}, []float64{1, 3, 2, 4})
+	if math.Abs(f.Slope-0.8) > eps || math.Abs(f.Intercept-0.5) > eps || math.Abs(f.R2-0.64) > eps {
+		t.Fatalf("noisy line: got %+v", f)
+	}
+	if f := linearFit([]float64{1}, []float64{1}); f != (fit{}) {
+		t.Fatalf("single point: got %+v", f)
+	}
+}
+
+func TestStats(t *testing.T) {
+	text := "The cat sat. The dog\nran away!\n\nA cat"
+	c := newStatsCounter(defaultTokenizer)
+	if err := c.add(strings.NewReader(text)); err != nil {
+		t.Fatal(err)
+	}
+	s := c.report()
+	if s.Tokens != 9 || s.Vocabulary != 7 || s.Hapax != 5 || s.Sentences != 3 {
+		t.Fatalf("bad counts: %+v", s)
+	}
+	if s.TypeTokenRatio != 7.0/9 || s.AvgWordLength != 26.0/9 || s.AvgSentenceLength != 3 {
+		t.Fatalf("bad averages: %+v", s)
+	}
+	expected := []heapsPoint{{1, 1}, {2, 2}, {3, 3}, {4, 3}, {6, 5}, {8, 7}, {9, 7}}
+	if !reflect.DeepEqual(expected, s.Heaps.Curve) {
+		t.Fatalf("Heaps curve: expected %v, got %v", expected, s.Heaps.Curve)
+	}
+
+	// statistics of many inputs are of their concatenation
+	if err := c.add(strings.NewReader(text)); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.report(); s.Tokens != 18 || s.Vocabulary != 7 || s.Sentences != 6 {
+		t.Fatalf("two inputs: bad counts: %+v", s)
+	}
+
+	if s := newStatsCounter(defaultTokenizer).report(); s.Tokens != 0 || s.Heaps.Curve == nil {
+		t.Fatalf("empty: got %+v", s)
+	}
+}
+
+func TestStatsSherlock(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	sherlock := loadSherlock(t)
+	freqs, err := wordFrequency(bytes.NewReader(sherlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newStatsCounter(defaultTokenizer)
+	if err := c.add(bytes.NewReader(sherlock)); err != nil {
+		t.Fatal(err)
+	}
+	s := c.report()
+	if s.Tokens != sumCounts(freqs) || s.Vocabulary != len(freqs) {
+		t.Fatalf("counts differ from wordFrequency: %d tokens, %d words", s.Tokens, s.Vocabulary)
+	}
+
+	// English text follows Zipf's law (slope about -1) and Heaps' law (beta
+	// between 0.4 and 0.8)
+	if s.Zipf.Slope > -0.8 || s.Zipf.Slope < -1.4 || s.Zipf.R2 < 0.9 {
+		t.Fatalf("bad Zipf fit: %+v", s.Zipf)
+	}
+	if s.Heaps.Beta < 0.4 || s.Heaps.Beta > 0.85 || s.Heaps.R2 < 0.9 {
+		t.Fatalf("bad Heaps fit: %+v", s.Heaps)
+	}
+	if last := s.Heaps.Curve[len(s.Heaps.Curve)-1]; last != (heapsPoint{s.Tokens, s.Vocabulary}) {
+		t.Fatalf("last Heaps point: expected %d, %d, got %v", s.Tokens, s.Vocabulary, last)
+	}
+	if s.AvgSentenceLength < 10 || s.AvgSentenceLength > 25 {
+		t.Fatalf("bad average sentence length: %v", s.AvgSentenceLength)
+	}
+}
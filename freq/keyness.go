@@ -0,0 +1,109 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// keyness is how distinctive a word is of a corpus compared to a reference
+// corpus.
+type keyness struct {
+	word          string
+	count         int     // in the corpus
+	refCount      int     // in the reference corpus
+	logLikelihood float64 // G² (Dunning, "Accurate Methods for the Statistics of Surprise and Coincidence", 1993)
+	chiSquare     float64 // Pearson's χ² of the 2x2 contingency table
+}
+
+// before reports if k is more distinctive than o: higher log-likelihood, ties
+// broken by word.
+func (k keyness) before(o keyness) bool {
+	if k.logLikelihood != o.logLikelihood {
+		return k.logLikelihood > o.logLikelihood
+	}
+	return k.word < o.word
+}
+
+// compareFrequencies returns the n most over-represented words in freqs
+// compared to ref, and the n most under-represented ones, by decreasing
+// log-likelihood. Every word of freqs or ref is scored.
+func compareFrequencies(freqs, ref map[string]int, n int) (over, under []keyness) {
+	total, refTotal := sumCounts(freqs), sumCounts(ref)
+	score := func(word string) {
+		a, b := freqs[word], ref[word]
+		k := keyness{
+			word:          word,
+			count:         a,
+			refCount:      b,
+			logLikelihood: logLikelihood(a, b, total, refTotal),
+			chiSquare:     chiSquare(a, b, total, refTotal),
+		}
+		// compare relative frequencies a/total and b/refTotal
+		switch diff := float64(a)*float64(refTotal) - float64(b)*float64(total); {
+		case diff > 0:
+			over = append(over, k)
+		case diff < 0:
+			under = append(under, k)
+		}
+	}
+
+	for word := range freqs {
+		score(word)
+	}
+	for word := range ref {
+		if _, ok := freqs[word]; !ok {
+			score(word)
+		}
+	}
+
+	for _, ks := range []*[]keyness{&over, &under} {
+		sort.Slice(*ks, func(i, j int) bool { return (*ks)[i].before((*ks)[j]) })
+		if len(*ks) > n {
+			*ks = (*ks)[:n]
+		}
+	}
+	return over, under
+}
+
+func sumCounts(freqs map[string]int) int {
+	total := 0
+	for _, n := range freqs {
+		total += n
+	}
+	return total
+}
+
+// logLikelihood returns the G² statistic of a word appearing a times in a
+// corpus of total words and b times in a reference corpus of refTotal words.
+func logLikelihood(a, b, total, refTotal int) float64 {
+	if a+b == 0 {
+		return 0
+	}
+	n := float64(total + refTotal)
+	e1 := float64(total) * float64(a+b) / n // expected counts
+	e2 := float64(refTotal) * float64(a+b) / n
+	return 2 * (xLogRatio(a, e1) + xLogRatio(b, e2))
+}
+
+// xLogRatio returns x*ln(x/e), 0 if x is 0.
+func xLogRatio(x int, e float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return float64(x) * math.Log(float64(x)/e)
+}
+
+// chiSquare returns Pearson's χ² statistic (without continuity correction) of
+// a word appearing a times in a corpus of total words and b times in a
+// reference corpus of refTotal words.
+func chiSquare(a, b, total, refTotal int) float64 {
+	// contingency table: word a b, other words c d
+	c, d := float64(total-a), float64(refTotal-b)
+	fa, fb := float64(a), float64(b)
+	denom := (fa + fb) * (c + d) * float64(total) * float64(refTotal)
+	if denom == 0 {
+		return 0
+	}
+	diff := fa*d - fb*c
+	return float64(total+refTotal) * diff * diff / denom
+}
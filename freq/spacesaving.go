@@ -0,0 +1,161 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// spaceSaving is a Space-Saving summary (Metwally et al, "Efficient
+// Computation of Frequent and Top-k Elements in Data Streams", 2005) of word
+// counts. It keeps at most k counters, when a new word comes and all the
+// counters are taken, the word replaces the least frequent one and inherits
+// its count.
+//
+// With k = ceil(1/epsilon) and n words counted, the estimated count of a word
+// is at most epsilon*n above its true count, and every word with a true count
+// above epsilon*n is in the summary.
+type spaceSaving struct {
+	k        int
+	counters map[string]*ssCounter
+	heap     ssHeap // min-heap by count
+	n        int    // number of words counted
+}
+
+type ssCounter struct {
+	word  string
+	count int // estimated count, an upper bound of the true count
+	err   int // maximal overestimation of count
+	index int // index in heap
+}
+
+// newSpaceSaving returns an empty summary with an error bound of epsilon (in
+// (0, 1]) times the number of counted words.
+func newSpaceSaving(epsilon float64) (*spaceSaving, error) {
+	if !(epsilon > 0 && epsilon <= 1) {
+		return nil, fmt.Errorf("epsilon must be in (0, 1], got %v", epsilon)
+	}
+	k := int(math.Ceil(1 / epsilon))
+	return &spaceSaving{
+		k:        k,
+		counters: make(map[string]*ssCounter, k),
+	}, nil
+}
+
+// add counts an occurrence of word.
+func (s *spaceSaving) add(word string) {
+	s.n++
+	if c, ok := s.counters[word]; ok {
+		c.count++
+		heap.Fix(&s.heap, c.index)
+		return
+	}
+
+	if len(s.heap) < s.k {
+		c := &ssCounter{word: word, count: 1}
+		s.counters[word] = c
+		heap.Push(&s.heap, c)
+		return
+	}
+
+	// replace the least frequent word
+	c := s.heap[0]
+	delete(s.counters, c.word)
+	c.word, c.err = word, c.count
+	c.count++
+	s.counters[word] = c
+	heap.Fix(&s.heap, 0)
+}
+
+// minCount returns the smallest count in the summary if it's full (an upper
+// bound of the count of words not in it), 0 otherwise.
+func (s *spaceSaving) minCount() int {
+	if len(s.heap) < s.k {
+		return 0
+	}
+	return s.heap[0].count
+}
+
+// merge adds the counts of other, which must have the same k, to s.
+// Words missing from one of the summaries are estimated with its minimal
+// count, so the error bound of the result is epsilon times the total number
+// of words (Agarwal et al, "Mergeable Summaries", 2012).
+func (s *spaceSaving) merge(other *spaceSaving) error {
+	if s.k != other.k {
+		return fmt.Errorf("can't merge summaries of different sizes (%d != %d)", s.k, other.k)
+	}
+
+	sMin, oMin := s.minCount(), other.minCount()
+	merged := make(map[string]*ssCounter, len(s.counters)+len(other.counters))
+	for word, c := range s.counters {
+		merged[word] = &ssCounter{word: word, count: c.count + oMin, err: c.err + oMin}
+	}
+	for word, c := range other.counters {
+		if m, ok := merged[word]; ok {
+			m.count += c.count - oMin
+			m.err += c.err - oMin
+			continue
+		}
+		merged[word] = &ssCounter{word: word, count: c.count + sMin, err: c.err + sMin}
+	}
+
+	// keep the k largest counts
+	all := make(ssHeap, 0, len(merged))
+	for _, c := range merged {
+		all = append(all, c)
+	}
+	heap.Init(&all)
+	for len(all) > s.k {
+		heap.Pop(&all)
+	}
+
+	s.counters = make(map[string]*ssCounter, s.k)
+	for i, c := range all {
+		c.index = i // not all counters were moved by the heap
+		s.counters[c.word] = c
+	}
+	s.heap = all
+	s.n += other.n
+	return nil
+}
+
+// top returns the n words with the highest estimated counts, see maxNWords.
+func (s *spaceSaving) top(n int) []freqStruct {
+	freqs := make(map[string]int, len(s.counters))
+	for word, c := range s.counters {
+		freqs[word] = c.count
+	}
+	return maxNWords(freqs, n)
+}
+
+// ssHeap is a min-heap of counters, ties are broken by word so merges are
+// deterministic.
+type ssHeap []*ssCounter
+
+func (h ssHeap) Len() int { return len(h) }
+
+func (h ssHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].word > h[j].word
+}
+
+func (h ssHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *ssHeap) Push(x any) {
+	c := x.(*ssCounter)
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *ssHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// corpusStats is a statistics report of a corpus.
+type corpusStats struct {
+	Tokens            int     `json:"tokens"`
+	Vocabulary        int     `json:"vocabulary"` // number of distinct words
+	TypeTokenRatio    float64 `json:"type_token_ratio"`
+	Hapax             int     `json:"hapax_legomena"` // number of words seen once
+	Zipf              fit     `json:"zipf"`           // log(count) by log(rank), slope is about -1
+	Heaps             heaps   `json:"heaps"`
+	AvgWordLength     float64 `json:"avg_word_length"` // in runes
+	Sentences         int     `json:"sentences"`
+	AvgSentenceLength float64 `json:"avg_sentence_length"` // in tokens
+}
+
+// fit is a least squares linear fit.
+type fit struct {
+	Slope     float64 `json:"slope"`
+	Intercept float64 `json:"intercept"`
+	R2        float64 `json:"r2"`
+}
+
+// heaps is the vocabulary growth: Vocabulary = K * Tokens^Beta (Heaps' law).
+type heaps struct {
+	K     float64      `json:"k"`
+	Beta  float64      `json:"beta"`
+	R2    float64      `json:"r2"` // of the log-log fit
+	Curve []heapsPoint `json:"curve"`
+}
+
+type heapsPoint struct {
+	Tokens     int `json:"tokens"`
+	Vocabulary int `json:"vocabulary"`
+}
+
+// heapsGrowth is the growth of the number of tokens between points of the
+// Heaps curve.
+const heapsGrowth = 1.25
+
+// sentenceEndRe matches text ending a sentence between two words. It's a
+// simple heuristic, abbreviations ("Mr.") end sentences too.
+var sentenceEndRe = regexp.MustCompile(`[.!?]`)
+
+// statsCounter collects corpus statistics, words are split by a tokenizer.
+type statsCounter struct {
+	t          *tokenizer
+	freqs      map[string]int // word -> count
+	tokens     int
+	runes      int // in tokens
+	sentences  int
+	inSentence bool // tokens since the last sentence end
+	curve      []heapsPoint
+	next       int // number of tokens of the next curve point
+}
+
+func newStatsCounter(t *tokenizer) *statsCounter {
+	return &statsCounter{t: t, freqs: make(map[string]int), next: 1}
+}
+
+// add counts the text in r. Sentences end at a paragraph break (an empty
+// line) or with ".", "!" or "?" between words, they don't span inputs.
+func (c *statsCounter) add(r io.Reader) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if strings.TrimSpace(line) == "" {
+			c.endSentence()
+		}
+		last := 0
+		for _, loc := range c.t.re.FindAllStringIndex(line, -1) {
+			if sentenceEndRe.MatchString(line[last:loc[0]]) {
+				c.endSentence()
+			}
+			last = loc[1]
+			if word, ok := c.t.filter(line[loc[0]:loc[1]]); ok {
+				c.addWord(word)
+			}
+		}
+		if sentenceEndRe.MatchString(line[last:]) {
+			c.endSentence()
+		}
+
+		if errors.Is(err, io.EOF) {
+			c.endSentence()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *statsCounter) addWord(word string) {
+	c.freqs[word]++
+	c.tokens++
+	c.runes += utf8.RuneCountInString(word)
+	c.inSentence = true
+	if c.tokens == c.next {
+		c.curve = append(c.curve, heapsPoint{c.tokens, len(c.freqs)})
+		c.next = int(float64(c.next)*heapsGrowth) + 1
+	}
+}
+
+func (c *statsCounter) endSentence() {
+	if c.inSentence {
+		c.sentences++
+		c.inSentence = false
+	}
+}
+
+// report returns the statistics of the text counted so far.
+func (c *statsCounter) report() corpusStats {
+	s := corpusStats{
+		Tokens:     c.tokens,
+		Vocabulary: len(c.freqs),
+		Sentences:  c.sentences,
+	}
+	if c.tokens == 0 {
+		s.Heaps.Curve = []heapsPoint{}
+		return s
+	}
+
+	s.TypeTokenRatio = float64(s.Vocabulary) / float64(s.Tokens)
+	s.AvgWordLength = float64(c.runes) / float64(s.Tokens)
+	if s.Sentences > 0 {
+		s.AvgSentenceLength = float64(s.Tokens) / float64(s.Sentences)
+	}
+
+	counts := make([]int, 0, len(c.freqs))
+	for _, n := range c.freqs {
+		counts = append(counts, n)
+		if n == 1 {
+			s.Hapax++
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+	xs, ys := make([]float64, len(counts)), make([]float64, len(counts))
+	for i, n := range counts {
+		xs[i], ys[i] = math.Log(float64(i+1)), math.Log(float64(n))
+	}
+	s.Zipf = linearFit(xs, ys)
+
+	s.Heaps.Curve = c.curve
+	if last := c.curve[len(c.curve)-1]; last.Tokens != c.tokens {
+		s.Heaps.Curve = append(s.Heaps.Curve, heapsPoint{c.tokens, len(c.freqs)})
+	}
+	xs, ys = xs[:0], ys[:0]
+	for _, p := range s.Heaps.Curve {
+		xs = append(xs, math.Log(float64(p.Tokens)))
+		ys = append(ys, math.Log(float64(p.Vocabulary)))
+	}
+	f := linearFit(xs, ys)
+	s.Heaps.K, s.Heaps.Beta, s.Heaps.R2 = math.Exp(f.Intercept), f.Slope, f.R2
+	return s
+}
+
+// linearFit returns the least squares fit of ys by xs. The fit is zero if
+// there are less than two distinct xs, R2 is 1 if ys are constant.
+func linearFit(xs, ys []float64) fit {
+	n := float64(len(xs))
+	var sx, sy, sxx, sxy, syy float64
+	for i, x := range xs {
+		y := ys[i]
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+		syy += y * y
+	}
+	vx := n*sxx - sx*sx
+	vy := n*syy - sy*sy
+	if vx <= 0 {
+		return fit{}
+	}
+
+	slope := (n*sxy - sx*sy) / vx
+	f := fit{Slope: slope, Intercept: (sy - slope*sx) / n, R2: 1}
+	if vy > 0 {
+		cov := n*sxy - sx*sy
+		f.R2 = cov * cov / (vx * vy)
+	}
+	return f
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"regexp"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:generate cp ../nlp/stopwords/en.txt stopwords.txt
+
+var (
+	// stopwords.txt is a copy of nlp/stopwords/en.txt, edit that one and run
+	// go generate.
+	//go:embed stopwords.txt
+	stopWordsData string
+
+	// stopWords are English stop words, in lower case.
+	stopWords = loadStopWords(stopWordsData)
+)
+
+func loadStopWords(data string) map[string]bool {
+	words := make(map[string]bool)
+	s := bufio.NewScanner(strings.NewReader(data))
+	for s.Scan() {
+		word := strings.TrimSpace(s.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words[word] = true
+	}
+	return words
+}
+
+// tokenizer splits text to words.
+type tokenizer struct {
+	re            *regexp.Regexp  // matches words
+	minLen        int             // minimal word length, in runes
+	caseSensitive bool            // don't lower case words
+	stopWords     map[string]bool // words to drop, in lower case
+}
+
+// words calls fn with every word in text, line by line like wordFrequency.
+func (t *tokenizer) words(text string, fn func(word string)) {
+	for text != "" {
+		line := text
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			line, text = text[:i], text[i+1:]
+		} else {
+			text = ""
+		}
+		for _, w := range t.re.FindAllString(line, -1) {
+			if word, ok := t.filter(w); ok {
+				fn(word)
+			}
+		}
+	}
+}
+
+// filter returns the word to count for a match of t.re, ok is false if the
+// match is dropped.
+func (t *tokenizer) filter(match string) (word string, ok bool) {
+	if t.minLen > 0 && utf8.RuneCountInString(match) < t.minLen {
+		return "", false
+	}
+	lower := strings.ToLower(match)
+	if t.stopWords[lower] {
+		return "", false
+	}
+	if t.caseSensitive {
+		return match, true
+	}
+	return lower, true
+}
+
+// frequency returns the count of every word in r, counting chunks of r in
+// parallel with workers goroutines (runtime.NumCPU() if workers <= 0).
+func (t *tokenizer) frequency(r io.Reader, workers int) (map[string]int, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	counts := make([]map[string]int, workers) // per worker
+	for i := range counts {
+		counts[i] = make(map[string]int)
+	}
+	err := countChunks(r, workers, func(worker int, chunk string) {
+		freqs := counts[worker]
+		t.words(chunk, func(word string) { freqs[word]++ })
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	freqs := counts[0]
+	for _, wfreqs := range counts[1:] {
+		for w, n := range wfreqs {
+			freqs[w] += n
+		}
+	}
+	return freqs, nil
+}
+
+// frequencyApprox is like frequency but counts in Space-Saving summaries with
+// an error bound of epsilon times the number of words, see spaceSaving.
+func (t *tokenizer) frequencyApprox(r io.Reader, epsilon float64, workers int) (*spaceSaving, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	summaries := make([]*spaceSaving, workers) // per worker
+	for i := range summaries {
+		s, err := newSpaceSaving(epsilon)
+		if err != nil {
+			return nil, err
+		}
+		summaries[i] = s
+	}
+	err := countChunks(r, workers, func(worker int, chunk string) {
+		t.words(chunk, summaries[worker].add)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total := summaries[0]
+	for _, s := range summaries[1:] {
+		total.merge(s) // same epsilon, can't fail
+	}
+	return total, nil
+}
@@ -0,0 +1,575 @@
+// Command nlp tokenizes, stems and indexes text from the command line, using
+// the same tokenization as the nlp package.
+//
+// Usage:
+//
+//	nlp <command> [options] [file ...]
+//
+// Commands:
+//
+//	tokenize  print tokens
+//	stem      print the stem of every word
+//	ngrams    print token n-grams
+//	freq      print token frequencies
+//	index     build an index file, every input file is a document
+//	search    search an index file
+//
+// Input is read from the files, or from stdin if there are none. Output is
+// plain text, JSON lines or CSV (the -format option). Run "nlp <command> -h"
+// for the command options.
+//
+// Index files start with a JSON line of the tokenizer options used by index,
+// followed by the index (see nlp.Index.WriteTo). search uses the same options.
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/osshu320/nlp"
+)
+
+const usage = `usage: nlp <command> [options] [file ...]
+
+commands:
+  tokenize  print tokens
+  stem      print the stem of every word
+  ngrams    print token n-grams
+  freq      print token frequencies
+  index     build an index file, every input file is a document
+  search    search an index file
+
+Run "nlp <command> -h" for the command options.
+`
+
+var commands = map[string]func(args []string, stdin io.Reader, stdout io.Writer) error{
+	"tokenize": tokenizeCmd,
+	"stem":     stemCmd,
+	"ngrams":   ngramsCmd,
+	"freq":     freqCmd,
+	"index":    indexCmd,
+	"search":   searchCmd,
+}
+
+// errUsage is returned on bad command line, the usage was already printed.
+var errUsage = errors.New("bad usage")
+
+func main() {
+	log.SetFlags(0)
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		log.Fatalf("error: %s", err)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %q\n%s", args[0], usage)
+		return errUsage
+	}
+	return cmd(args[1:], stdin, stdout)
+}
+
+// options are the options shared by commands.
+type options struct {
+	flags        *flag.FlagSet
+	lang         string
+	stopWords    bool
+	rules        bool
+	contractions bool
+	format       string
+}
+
+func newOptions(name, args string) *options {
+	o := options{flags: flag.NewFlagSet(name, flag.ContinueOnError)}
+	o.flags.Usage = func() {
+		fmt.Fprintf(o.flags.Output(), "usage: nlp %s [options] %s\n", name, args)
+		o.flags.PrintDefaults()
+	}
+	o.flags.StringVar(&o.format, "format", "text", "output format: text, json or csv")
+	return &o
+}
+
+// tokenizerFlags adds the tokenizer options.
+func (o *options) tokenizerFlags() {
+	o.flags.StringVar(&o.lang, "lang", "en", `stemmer language ("none" to disable stemming)`)
+	o.flags.BoolVar(&o.stopWords, "stop", false, "drop built-in stop words for -lang")
+	o.flags.BoolVar(&o.rules, "rules", false, "keep URLs, emails, hashtags, mentions and numbers as tokens")
+	o.flags.BoolVar(&o.contractions, "contractions", false, `expand contractions ("don't" -> "do", "not")`)
+}
+
+func (o *options) parse(args []string) error {
+	if err := o.flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	switch o.format {
+	case "text", "json", "csv":
+		return nil
+	}
+	fmt.Fprintf(o.flags.Output(), "unknown format: %q\n", o.format)
+	o.flags.Usage()
+	return errUsage
+}
+
+func (o *options) tokenizer() (*nlp.Tokenizer, error) {
+	opts := []nlp.Option{nlp.WithLanguage(o.lang)}
+	if o.stopWords {
+		lang := o.lang
+		if lang == "none" {
+			lang = "en"
+		}
+		sw, err := nlp.BuiltinStopWords(lang)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, nlp.WithStopWords(sw))
+	}
+	if o.rules {
+		opts = append(opts, nlp.WithRules(nlp.URLRule, nlp.EmailRule, nlp.HashtagRule, nlp.MentionRule, nlp.NumberRule))
+	}
+	if o.contractions {
+		opts = append(opts, nlp.WithContractions())
+	}
+	return nlp.NewTokenizer(opts...)
+}
+
+// settings are the tokenizer options stored in index files.
+type settings struct {
+	Lang         string `json:"lang"`
+	StopWords    bool   `json:"stop_words"`
+	Rules        bool   `json:"rules"`
+	Contractions bool   `json:"contractions"`
+}
+
+func (o *options) settings() settings {
+	return settings{o.lang, o.stopWords, o.rules, o.contractions}
+}
+
+// readIndex reads an index file written by index, with the tokenizer options
+// it was built with.
+func readIndex(r *bufio.Reader) (*nlp.Index, error) {
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing tokenizer options", nlp.ErrBadIndex)
+	}
+	var s settings
+	if err := json.Unmarshal(line, &s); err != nil {
+		return nil, fmt.Errorf("%w: bad tokenizer options: %s", nlp.ErrBadIndex, err)
+	}
+
+	o := options{lang: s.Lang, stopWords: s.StopWords, rules: s.Rules, contractions: s.Contractions}
+	tok, err := o.tokenizer()
+	if err != nil {
+		return nil, err
+	}
+	ix := nlp.NewIndex(tok)
+	if _, err := ix.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// eachInput calls fn with every input file, or with stdin (named "-") if
+// there are no files.
+func eachInput(files []string, stdin io.Reader, fn func(name string, r io.Reader) error) error {
+	if len(files) == 0 {
+		return fn("-", stdin)
+	}
+
+	for _, name := range files {
+		err := func() error {
+			file, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			return fn(name, file)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record is an output record.
+type record interface {
+	text() string  // text output line
+	row() []string // CSV row
+}
+
+// output writes records in one of the output formats.
+type output struct {
+	format string
+	header []string // CSV header
+	w      *bufio.Writer
+	csv    *csv.Writer
+	enc    *json.Encoder
+}
+
+func newOutput(format string, w io.Writer, header ...string) *output {
+	o := output{format: format, header: header, w: bufio.NewWriter(w)}
+	o.csv = csv.NewWriter(o.w)
+	o.enc = json.NewEncoder(o.w)
+	return &o
+}
+
+func (o *output) write(rec record) error {
+	switch o.format {
+	case "json":
+		return o.enc.Encode(rec)
+	case "csv":
+		if err := o.writeHeader(); err != nil {
+			return err
+		}
+		return o.csv.Write(rec.row())
+	}
+	_, err := fmt.Fprintln(o.w, rec.text())
+	return err
+}
+
+// writeHeader writes the CSV header if it wasn't written yet.
+func (o *output) writeHeader() error {
+	if o.header == nil {
+		return nil
+	}
+	err := o.csv.Write(o.header)
+	o.header = nil
+	return err
+}
+
+// flush writes buffered output, CSV output without records has a header.
+func (o *output) flush() error {
+	if o.format == "csv" {
+		if err := o.writeHeader(); err != nil {
+			return err
+		}
+	}
+	o.csv.Flush()
+	if err := o.csv.Error(); err != nil {
+		return err
+	}
+	return o.w.Flush()
+}
+
+type tokenRecord struct {
+	File  string        `json:"file"`
+	Text  string        `json:"text"`
+	Norm  string        `json:"norm"`
+	Type  nlp.TokenType `json:"type"`
+	Start int           `json:"start"`
+	End   int           `json:"end"`
+}
+
+func (r tokenRecord) text() string { return r.Norm }
+
+func (r tokenRecord) row() []string {
+	return []string{r.File, r.Text, r.Norm, string(r.Type), strconv.Itoa(r.Start), strconv.Itoa(r.End)}
+}
+
+func tokenizeCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := newOptions("tokenize", "[file ...]")
+	opts.tokenizerFlags()
+	if err := opts.parse(args); err != nil {
+		return err
+	}
+	tok, err := opts.tokenizer()
+	if err != nil {
+		return err
+	}
+
+	out := newOutput(opts.format, stdout, "file", "text", "norm", "type", "start", "end")
+	err = eachInput(opts.flags.Args(), stdin, func(name string, r io.Reader) error {
+		s := tok.NewScanner(r)
+		for s.Scan() {
+			t := s.Token()
+			if err := out.write(tokenRecord{name, t.Text, t.Norm, t.Type, t.Start, t.End}); err != nil {
+				return err
+			}
+		}
+		return s.Err()
+	})
+	if err != nil {
+		return err
+	}
+	return out.flush()
+}
+
+type stemRecord struct {
+	Word string `json:"word"`
+	Stem string `json:"stem"`
+}
+
+func (r stemRecord) text() string  { return r.Word + "\t" + r.Stem }
+func (r stemRecord) row() []string { return []string{r.Word, r.Stem} }
+
+func stemCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := newOptions("stem", "[file ...]")
+	opts.tokenizerFlags()
+	if err := opts.parse(args); err != nil {
+		return err
+	}
+	tok, err := opts.tokenizer()
+	if err != nil {
+		return err
+	}
+
+	out := newOutput(opts.format, stdout, "word", "stem")
+	err = eachInput(opts.flags.Args(), stdin, func(name string, r io.Reader) error {
+		s := tok.NewScanner(r)
+		for s.Scan() {
+			t := s.Token()
+			if err := out.write(stemRecord{t.Text, t.Norm}); err != nil {
+				return err
+			}
+		}
+		return s.Err()
+	})
+	if err != nil {
+		return err
+	}
+	return out.flush()
+}
+
+type ngramRecord struct {
+	File  string `json:"file"`
+	NGram string `json:"ngram"`
+}
+
+func (r ngramRecord) text() string  { return r.NGram }
+func (r ngramRecord) row() []string { return []string{r.File, r.NGram} }
+
+func ngramsCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := newOptions("ngrams", "[file ...]")
+	opts.tokenizerFlags()
+	minN := opts.flags.Int("min", 2, "minimal n-gram size")
+	maxN := opts.flags.Int("max", 2, "maximal n-gram size")
+	if err := opts.parse(args); err != nil {
+		return err
+	}
+	tok, err := opts.tokenizer()
+	if err != nil {
+		return err
+	}
+	if *minN < 1 || *maxN < *minN {
+		return fmt.Errorf("bad n-gram sizes: -min %d -max %d", *minN, *maxN)
+	}
+
+	out := newOutput(opts.format, stdout, "file", "ngram")
+	err = eachInput(opts.flags.Args(), stdin, func(name string, r io.Reader) error {
+		var tokens []string
+		s := tok.NewScanner(r)
+		for s.Scan() {
+			tokens = append(tokens, s.Token().Norm)
+		}
+		if err := s.Err(); err != nil {
+			return err
+		}
+
+		for _, ngram := range nlp.NGrams(tokens, nlp.NGramOptions{MinN: *minN, MaxN: *maxN}) {
+			if err := out.write(ngramRecord{name, ngram}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return out.flush()
+}
+
+type freqRecord struct {
+	Token string `json:"token"`
+	Count int    `json:"count"`
+}
+
+func (r freqRecord) text() string  { return fmt.Sprintf("%s\t%d", r.Token, r.Count) }
+func (r freqRecord) row() []string { return []string{r.Token, strconv.Itoa(r.Count)} }
+
+func freqCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := newOptions("freq", "[file ...]")
+	opts.tokenizerFlags()
+	top := opts.flags.Int("top", 0, "print only the top N tokens (0 for all)")
+	if err := opts.parse(args); err != nil {
+		return err
+	}
+	tok, err := opts.tokenizer()
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int)
+	err = eachInput(opts.flags.Args(), stdin, func(name string, r io.Reader) error {
+		s := tok.NewScanner(r)
+		for s.Scan() {
+			counts[s.Token().Norm]++
+		}
+		return s.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	freqs := make([]freqRecord, 0, len(counts))
+	for token, count := range counts {
+		freqs = append(freqs, freqRecord{token, count})
+	}
+	sort.Slice(freqs, func(i, j int) bool {
+		if freqs[i].Count != freqs[j].Count {
+			return freqs[i].Count > freqs[j].Count
+		}
+		return freqs[i].Token < freqs[j].Token
+	})
+	if *top > 0 && len(freqs) > *top {
+		freqs = freqs[:*top]
+	}
+
+	out := newOutput(opts.format, stdout, "token", "count")
+	for _, f := range freqs {
+		if err := out.write(f); err != nil {
+			return err
+		}
+	}
+	return out.flush()
+}
+
+func indexCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := newOptions("index", "-o index-file [file ...]")
+	opts.tokenizerFlags()
+	indexFile := opts.flags.String("o", "", "index file to write (required)")
+	if err := opts.parse(args); err != nil {
+		return err
+	}
+	if *indexFile == "" {
+		fmt.Fprintln(opts.flags.Output(), "missing -o")
+		opts.flags.Usage()
+		return errUsage
+	}
+	tok, err := opts.tokenizer()
+	if err != nil {
+		return err
+	}
+
+	settings, err := json.Marshal(opts.settings())
+	if err != nil {
+		return err
+	}
+
+	ix := nlp.NewIndex(tok)
+	err = eachInput(opts.flags.Args(), stdin, func(name string, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		ix.Add(name, string(data))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*indexFile)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	if _, err := w.Write(append(settings, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := ix.WriteTo(w); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+type resultRecord struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+}
+
+func (r resultRecord) text() string { return fmt.Sprintf("%s\t%.4f", r.ID, r.Score) }
+
+func (r resultRecord) row() []string {
+	return []string{r.ID, strconv.FormatFloat(r.Score, 'f', -1, 64)}
+}
+
+func searchCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := newOptions("search", "-i index-file query")
+	indexFile := opts.flags.String("i", "", "index file to search (required)")
+	ranking := opts.flags.String("ranking", "bm25", "ranking function: bm25 or tfidf")
+	top := opts.flags.Int("top", 0, "print only the top N results (0 for all)")
+	if err := opts.parse(args); err != nil {
+		return err
+	}
+	if *indexFile == "" || opts.flags.NArg() == 0 {
+		fmt.Fprintln(opts.flags.Output(), "missing -i or query")
+		opts.flags.Usage()
+		return errUsage
+	}
+
+	var rank nlp.Ranking
+	switch *ranking {
+	case "bm25":
+		rank = nlp.BM25
+	case "tfidf":
+		rank = nlp.TFIDF
+	default:
+		return fmt.Errorf("unknown ranking: %q", *ranking)
+	}
+
+	file, err := os.Open(*indexFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	ix, err := readIndex(bufio.NewReader(file))
+	if err != nil {
+		return fmt.Errorf("%s: %w", *indexFile, err)
+	}
+
+	results, err := ix.Search(strings.Join(opts.flags.Args(), " "), rank)
+	if err != nil {
+		return err
+	}
+	if *top > 0 && len(results) > *top {
+		results = results[:*top]
+	}
+
+	out := newOutput(opts.format, stdout, "id", "score")
+	for _, r := range results {
+		if err := out.write(resultRecord{r.ID, r.Score}); err != nil {
+			return err
+		}
+	}
+	return out.flush()
+}
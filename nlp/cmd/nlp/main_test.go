@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/osshu320/nlp"
+	"github.com/stretchr/testify/require"
+)
+
+func runCmd(t *testing.T, stdin string, args ...string) string {
+	var out bytes.Buffer
+	err := run(args, strings.NewReader(stdin), &out)
+	require.NoError(t, err)
+	return out.String()
+}
+
+func TestTokenize(t *testing.T) {
+	out := runCmd(t, "Who's on first?", "tokenize")
+	require.Equal(t, "who\non\nfirst\n", out)
+
+	out = runCmd(t, "Don't go", "tokenize", "-lang", "none", "-contractions", "-format", "json")
+	expected := `{"file":"-","text":"Do","norm":"do","type":"word","start":0,"end":2}
+{"file":"-","text":"n't","norm":"not","type":"word","start":2,"end":5}
+{"file":"-","text":"go","norm":"go","type":"word","start":6,"end":8}
+`
+	require.Equal(t, expected, out)
+
+	out = runCmd(t, "see https://go.dev", "tokenize", "-rules", "-format", "csv")
+	expected = `file,text,norm,type,start,end
+-,see,see,word,0,3
+-,https://go.dev,https://go.dev,url,4,18
+`
+	require.Equal(t, expected, out)
+}
+
+func TestStem(t *testing.T) {
+	out := runCmd(t, "Running\nchats", "stem", "-format", "csv")
+	require.Equal(t, "word,stem\nRunning,run\nchats,chat\n", out)
+
+	out = runCmd(t, "chats", "stem", "-lang", "fr")
+	require.Equal(t, "chats\tchat\n", out)
+
+	// same tokenization as the tokenize command
+	out = runCmd(t, `The dogs, "Holmes" said.`, "stem", "-stop")
+	require.Equal(t, "dogs\tdog\nHolmes\tholm\nsaid\tsaid\n", out)
+}
+
+func TestNGrams(t *testing.T) {
+	out := runCmd(t, "Sherlock Holmes smiled", "ngrams", "-lang", "none", "-max", "3")
+	require.Equal(t, "sherlock holmes\nholmes smiled\nsherlock holmes smiled\n", out)
+}
+
+func TestFreq(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(file, []byte("the cat and the hat"), 0o644))
+
+	out := runCmd(t, "", "freq", "-stop", "-top", "2", file, file)
+	require.Equal(t, "cat\t2\nhat\t2\n", out)
+
+	out = runCmd(t, "b a b", "freq", "-format", "json")
+	require.Equal(t, "{\"token\":\"b\",\"count\":2}\n{\"token\":\"a\",\"count\":1}\n", out)
+}
+
+func TestIndexSearch(t *testing.T) {
+	dir := t.TempDir()
+	docs := map[string]string{
+		"hound.txt": "The Hound of the Baskervilles",
+		"sign.txt":  "The Sign of the Four",
+	}
+	var files []string
+	for name, text := range docs {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(file, []byte(text), 0o644))
+		files = append(files, file)
+	}
+	indexFile := filepath.Join(dir, "index")
+
+	runCmd(t, "", append([]string{"index", "-o", indexFile}, files...)...)
+	out := runCmd(t, "", "search", "-i", indexFile, "-format", "csv", "hounds")
+	require.Equal(t, "id,score\n"+filepath.Join(dir, "hound.txt")+",0.6931471805599453\n", out)
+
+	out = runCmd(t, "", "search", "-i", indexFile, "moriarty")
+	require.Equal(t, "", out)
+	out = runCmd(t, "", "search", "-i", indexFile, "-format", "csv", "moriarty")
+	require.Equal(t, "id,score\n", out)
+
+	err := run([]string{"search", "-i", filepath.Join(dir, "missing"), "x"}, nil, &bytes.Buffer{})
+	require.Error(t, err)
+
+	// search uses the tokenizer options of index
+	runCmd(t, "", append([]string{"index", "-o", indexFile, "-lang", "none", "-stop"}, files...)...)
+	out = runCmd(t, "", "search", "-i", indexFile, "-format", "csv", "Hound")
+	require.Equal(t, "id,score\n"+filepath.Join(dir, "hound.txt")+",0.6931471805599453\n", out)
+	out = runCmd(t, "", "search", "-i", indexFile, "hounds") // not stemmed
+	require.Equal(t, "", out)
+
+	err = run([]string{"search", "-i", indexFile, "-lang", "en", "hounds"}, nil, &bytes.Buffer{})
+	require.ErrorIs(t, err, errUsage)
+
+	// plain nlp index without tokenizer options
+	var buf bytes.Buffer
+	_, err = nlp.NewIndex(nil).WriteTo(&buf)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(indexFile, buf.Bytes(), 0o644))
+	err = run([]string{"search", "-i", indexFile, "hound"}, nil, &bytes.Buffer{})
+	require.ErrorIs(t, err, nlp.ErrBadIndex)
+}
+
+func TestBadUsage(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"nope"},
+		{"tokenize", "-format", "xml"},
+		{"tokenize", "-nope"},
+		{"index"},
+		{"search", "-i", "index"},
+	} {
+		err := run(args, strings.NewReader(""), &bytes.Buffer{})
+		require.ErrorIs(t, err, errUsage, "%v", args)
+	}
+}
@@ -0,0 +1,64 @@
+package nlp
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// contractionSuffixes are the expansions of English contraction
+	// suffixes (after the apostrophe, "n't" is "t").
+	contractionSuffixes = map[string]string{
+		"t":  "not",
+		"re": "are",
+		"ve": "have",
+		"ll": "will",
+		"m":  "am",
+		"d":  "would",
+		"s":  "is",
+	}
+
+	// contractionStems are words changed by a "n't" contraction.
+	contractionStems = map[string]string{
+		"ca":  "can",
+		"wo":  "will",
+		"sha": "shall",
+	}
+
+	// isContractions are the words where "'s" is "is" (or "us" in "let's"),
+	// otherwise it's a possessive ("Holmes's").
+	isContractions = map[string]bool{
+		"it": true, "he": true, "she": true, "that": true, "what": true,
+		"where": true, "who": true, "there": true, "here": true, "how": true,
+	}
+)
+
+// splitContraction splits an English contraction to the size of its first
+// part in word and the expansions of the two parts ("don't" -> 2, "do",
+// "not"). An empty expansion means the part is not changed.
+// ok is false if word is not a contraction.
+func splitContraction(word string) (size int, first, second string, ok bool) {
+	i := strings.LastIndexAny(word, "'’‘ʼ")
+	if i <= 0 {
+		return 0, "", "", false
+	}
+	_, aposSize := utf8.DecodeRuneInString(word[i:])
+	stem, suffix := strings.ToLower(word[:i]), strings.ToLower(word[i+aposSize:])
+
+	second, ok = contractionSuffixes[suffix]
+	switch {
+	case !ok:
+		return 0, "", "", false
+	case suffix == "t":
+		if len(stem) < 2 || stem[len(stem)-1] != 'n' {
+			return 0, "", "", false
+		}
+		i-- // "n't"
+		first = contractionStems[stem[:len(stem)-1]]
+	case suffix == "s" && stem == "let":
+		second = "us"
+	case suffix == "s" && !isContractions[stem]:
+		return 0, "", "", false
+	}
+	return i, first, second, true
+}
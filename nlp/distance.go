@@ -0,0 +1,79 @@
+package nlp
+
+// Levenshtein returns the edit distance between a and b: the number of rune
+// insertions, deletions and substitutions needed to change a to b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j-1]+cost, prev[j]+1, curr[j-1]+1)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// DamerauLevenshtein returns the Damerau-Levenshtein distance between a and
+// b: like Levenshtein but a transposition of two adjacent runes ("teh" ->
+// "the") counts as a single edit. Unlike the restricted (optimal string
+// alignment) variant, it is a metric: "ca" -> "abc" is 2.
+func DamerauLevenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	inf := len(ra) + len(rb)
+
+	// d[i+1][j+1] is the distance between ra[:i] and rb[:j]
+	d := make([][]int, len(ra)+2)
+	for i := range d {
+		d[i] = make([]int, len(rb)+2)
+	}
+	d[0][0] = inf
+	for i := 0; i <= len(ra); i++ {
+		d[i+1][0] = inf
+		d[i+1][1] = i
+	}
+	for j := 0; j <= len(rb); j++ {
+		d[0][j+1] = inf
+		d[1][j+1] = j
+	}
+
+	last := make(map[rune]int) // rune -> last row it was seen in ra
+	for i := 1; i <= len(ra); i++ {
+		lastMatch := 0 // last column in this row where ra[i-1] == rb[j-1]
+		for j := 1; j <= len(rb); j++ {
+			k, l := last[rb[j-1]], lastMatch
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+				lastMatch = j
+			}
+			d[i+1][j+1] = minInt(
+				d[i][j]+cost,              // substitution
+				d[i+1][j]+1,               // insertion
+				d[i][j+1]+1,               // deletion
+				d[k][l]+(i-k-1)+1+(j-l-1), // transposition
+			)
+		}
+		last[ra[i-1]] = i
+	}
+	return d[len(ra)+1][len(rb)+1]
+}
+
+func minInt(v int, vs ...int) int {
+	for _, n := range vs {
+		if n < v {
+			v = n
+		}
+	}
+	return v
+}
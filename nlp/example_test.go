@@ -2,6 +2,7 @@ package nlp_test
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/osshu320/nlp"
 )
@@ -15,6 +16,159 @@ func ExampleTokenize() {
 	// [who on first]
 }
 
+func ExampleNewTokenizer() {
+	tok, err := nlp.NewTokenizer(nlp.WithLanguage("es"))
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(tok.Tokenize("Los chicos comen chocolates"))
+
+	// Output:
+	// [los chic com chocolat]
+}
+
+func ExampleWithStopWords() {
+	stopWords, err := nlp.BuiltinStopWords("en")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	tok, err := nlp.NewTokenizer(nlp.WithStopWords(stopWords))
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(tok.Tokenize("Who's on first?"))
+
+	// Output:
+	// [first]
+}
+
+func ExampleTokenizeWithOffsets() {
+	text := "The dogs barked"
+	for _, tok := range nlp.TokenizeWithOffsets(text) {
+		fmt.Printf("%d: %s [%d:%d] -> %s\n", tok.Index, tok.Text, tok.Start, tok.End, tok.Norm)
+	}
+
+	// Output:
+	// 0: The [0:3] -> the
+	// 1: dogs [4:8] -> dog
+	// 2: barked [9:15] -> bark
+}
+
+func ExampleScanner() {
+	r := strings.NewReader("Elementary, my dear Watson")
+	s := nlp.NewScanner(r)
+	for s.Scan() {
+		fmt.Println(s.Token().Norm)
+	}
+	if err := s.Err(); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// Output:
+	// elementari
+	// my
+	// dear
+	// watson
+}
+
+func ExampleIndex() {
+	ix := nlp.NewIndex(nil)
+	ix.Add("hound", "The Hound of the Baskervilles")
+	ix.Add("sign", "The Sign of the Four")
+	ix.Add("study", "A Study in Scarlet")
+
+	results, err := ix.Search(`hound OR "sign of the four"`, nlp.BM25)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	for _, r := range results {
+		fmt.Println(r.ID)
+	}
+
+	// Output:
+	// sign
+	// hound
+}
+
+func ExampleDetectLanguage() {
+	text := "Es war einmal ein kleines Mädchen, das bei seiner Großmutter lebte."
+	scores := nlp.DetectLanguage(text)
+	fmt.Println(scores[0].Language)
+
+	tok, err := nlp.NewTokenizer(nlp.WithLanguage(scores[0].Language))
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(tok.Tokenize(text))
+
+	// Output:
+	// de
+	// [es war einmal ein klein madch das bei sein grossmutt lebt]
+}
+
+func ExampleVocabulary() {
+	v := nlp.NewVocabulary(nil)
+	v.Add("Sherlock Holmes and Doctor Watson. Holmes smiled.")
+	v.Add("Mr. Sherlock Holmes, who was usually very late in the mornings")
+
+	for _, s := range v.Suggest("Homles", 2, 3) {
+		fmt.Println(s.Word, s.Distance, s.Count)
+	}
+
+	// Output:
+	// holmes 1 3
+}
+
+func ExampleWithRules() {
+	tok, err := nlp.NewTokenizer(
+		nlp.WithRules(nlp.URLRule, nlp.HashtagRule),
+		nlp.WithContractions(),
+	)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	for _, t := range tok.TokenizeWithOffsets("Don't miss https://go.dev #golang") {
+		fmt.Println(t.Type, t.Text, t.Norm)
+	}
+
+	// Output:
+	// word Do do
+	// word n't not
+	// word miss miss
+	// url https://go.dev https://go.dev
+	// hashtag #golang #golang
+}
+
+func ExampleTagger() {
+	tok, err := nlp.NewTokenizer(nlp.WithContractions())
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	tokens := tok.TokenizeWithOffsets("Holmes didn't see the old man")
+	tags := nlp.EnglishTagger().Tag(tokens)
+	for i, t := range tokens {
+		fmt.Println(t.Text, tags[i])
+	}
+
+	// Output:
+	// Holmes PROPN
+	// did AUX
+	// n't PART
+	// see VERB
+	// the DET
+	// old ADJ
+	// man NOUN
+}
+
 /*
 Test discovery:
 For every file ending with _test.go, run every function that matches either:
@@ -0,0 +1,226 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Ranking is a ranking function for search results.
+type Ranking int
+
+const (
+	BM25 Ranking = iota
+	TFIDF
+)
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Result is a search result.
+type Result struct {
+	ID    string
+	Score float64
+}
+
+// Index is an in-memory inverted index of documents.
+// It is safe for concurrent use.
+type Index struct {
+	tokenizer *Tokenizer
+
+	mu       sync.RWMutex
+	docs     map[string]*docInfo
+	postings map[string]map[string][]int // term -> doc ID -> positions
+	totalLen int                         // sum of document lengths
+}
+
+type docInfo struct {
+	length int      // number of tokens
+	gaps   int      // number of positions of dropped words, up to the last token
+	terms  []string // distinct terms, used by Remove
+}
+
+// NewIndex returns a new Index that uses t to tokenize documents and queries.
+// If t is nil, the default (English) tokenizer is used.
+func NewIndex(t *Tokenizer) *Index {
+	if t == nil {
+		t = defaultTokenizer
+	}
+	return &Index{
+		tokenizer: t,
+		docs:      make(map[string]*docInfo),
+		postings:  make(map[string]map[string][]int),
+	}
+}
+
+// Add adds a document to the index, replacing the document with the same ID.
+func (ix *Index) Add(id, text string) {
+	positions := make(map[string][]int) // term -> positions
+	// stop words leave gaps in positions, phrases don't match over them
+	tokens := ix.tokenizer.tokenize(text, true)
+	for _, tok := range tokens {
+		positions[tok.Norm] = append(positions[tok.Norm], tok.Index)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+	doc := &docInfo{length: len(tokens)}
+	if n := len(tokens); n > 0 {
+		doc.gaps = tokens[n-1].Index + 1 - n
+	}
+	for term, pos := range positions {
+		docs, ok := ix.postings[term]
+		if !ok {
+			docs = make(map[string][]int)
+			ix.postings[term] = docs
+		}
+		docs[id] = pos
+		doc.terms = append(doc.terms, term)
+	}
+	ix.docs[id] = doc
+	ix.totalLen += doc.length
+}
+
+// Remove removes a document from the index, it returns false if the document
+// is not in the index.
+func (ix *Index) Remove(id string) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	return ix.remove(id)
+}
+
+func (ix *Index) remove(id string) bool {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return false
+	}
+
+	for _, term := range doc.terms {
+		docs := ix.postings[term]
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.docs, id)
+	ix.totalLen -= doc.length
+	return true
+}
+
+// Len returns the number of documents in the index.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.docs)
+}
+
+// Search returns documents matching query, best match first.
+//
+// A query is a list of words that must all appear in the document. Words can
+// be combined with AND, OR & NOT (in upper case) and grouped with
+// parenthesis. Text in double quotes is a phrase, its words must appear in
+// the document in the same order, stop words in the phrase match any stop
+// word. For example:
+//
+//	"sherlock holmes" AND (violin OR pipe) NOT moriarty
+func (ix *Index) Search(query string, ranking Ranking) ([]Result, error) {
+	q, err := parseQuery(query, ix.tokenizer)
+	if err != nil {
+		return nil, err
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var results []Result
+	for id := range q.match(ix) {
+		results = append(results, Result{ID: id, Score: ix.score(id, q.terms(), ranking)})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+// score returns the score of a document for the query terms.
+func (ix *Index) score(id string, terms []string, ranking Ranking) float64 {
+	n := float64(len(ix.docs))
+	avgLen := float64(ix.totalLen) / n
+	docLen := float64(ix.docs[id].length)
+
+	score := 0.0
+	for _, term := range terms {
+		tf := float64(len(ix.postings[term][id]))
+		if tf == 0 {
+			continue
+		}
+		df := float64(len(ix.postings[term]))
+
+		switch ranking {
+		case TFIDF:
+			score += (1 + math.Log(tf)) * math.Log(1+n/df)
+		default: // BM25
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*docLen/avgLen))
+		}
+	}
+	return score
+}
+
+// docSet is a set of document IDs.
+type docSet map[string]bool
+
+// allDocs returns all the documents in the index.
+func (ix *Index) allDocs() docSet {
+	docs := make(docSet, len(ix.docs))
+	for id := range ix.docs {
+		docs[id] = true
+	}
+	return docs
+}
+
+// termDocs returns the documents containing term.
+func (ix *Index) termDocs(term string) docSet {
+	docs := make(docSet, len(ix.postings[term]))
+	for id := range ix.postings[term] {
+		docs[id] = true
+	}
+	return docs
+}
+
+// phraseDocs returns the documents containing terms in sequence, offsets are
+// the positions of terms relative to the first one.
+func (ix *Index) phraseDocs(terms []string, offsets []int) docSet {
+	docs := make(docSet)
+	for id, positions := range ix.postings[terms[0]] {
+		for _, pos := range positions {
+			if ix.phraseAt(id, terms[1:], offsets[1:], pos) {
+				docs[id] = true
+				break
+			}
+		}
+	}
+	return docs
+}
+
+// phraseAt reports if terms appear at their offsets from pos in document id.
+func (ix *Index) phraseAt(id string, terms []string, offsets []int, pos int) bool {
+	for i, term := range terms {
+		positions := ix.postings[term][id]
+		j := sort.SearchInts(positions, pos+offsets[i])
+		if j == len(positions) || positions[j] != pos+offsets[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,285 @@
+package nlp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+)
+
+// Index file format, all numbers are unsigned varints unless noted:
+//
+//	magic    "NLPI"
+//	version  1
+//	docs     count, then per document (sorted by ID): ID length, ID, number of tokens,
+//	           number of gaps
+//	terms    count, then per term (sorted):
+//	           shared prefix length with previous term, suffix length, suffix
+//	           number of postings, then per posting:
+//	             document number delta, number of positions, position deltas
+//	checksum CRC32 (IEEE) of all the above, 4 bytes little endian
+//
+// Document numbers are indices in the sorted list of documents. Gaps are the
+// positions of words dropped by the tokenizer, positions in a document are less
+// than its number of tokens plus its number of gaps.
+
+const (
+	indexMagic   = "NLPI"
+	indexVersion = 1
+)
+
+// ErrBadIndex is returned when reading a corrupt or unsupported index file.
+var ErrBadIndex = errors.New("bad index file")
+
+// WriteTo writes the index to w. It implements io.WriterTo.
+func (ix *Index) WriteTo(w io.Writer) (int64, error) {
+	ix.mu.RLock()
+	data := ix.encode()
+	ix.mu.RUnlock()
+
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+func (ix *Index) encode() []byte {
+	var buf []byte
+	putUvarint := func(v int) {
+		buf = binary.AppendUvarint(buf, uint64(v))
+	}
+	putString := func(s string) {
+		putUvarint(len(s))
+		buf = append(buf, s...)
+	}
+
+	buf = append(buf, indexMagic...)
+	putUvarint(indexVersion)
+
+	ids := make([]string, 0, len(ix.docs))
+	for id := range ix.docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	docNums := make(map[string]int, len(ids))
+	putUvarint(len(ids))
+	for i, id := range ids {
+		docNums[id] = i
+		putString(id)
+		putUvarint(ix.docs[id].length)
+		putUvarint(ix.docs[id].gaps)
+	}
+
+	terms := make([]string, 0, len(ix.postings))
+	for term := range ix.postings {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	putUvarint(len(terms))
+	prev := ""
+	for _, term := range terms {
+		shared := commonPrefixLen(prev, term)
+		putUvarint(shared)
+		putString(term[shared:])
+		prev = term
+
+		docs := ix.postings[term]
+		nums := make([]int, 0, len(docs))
+		for id := range docs {
+			nums = append(nums, docNums[id])
+		}
+		sort.Ints(nums)
+		putUvarint(len(nums))
+		prevNum := 0
+		for _, num := range nums {
+			putUvarint(num - prevNum)
+			prevNum = num
+
+			positions := docs[ids[num]]
+			putUvarint(len(positions))
+			prevPos := 0
+			for _, pos := range positions {
+				putUvarint(pos - prevPos)
+				prevPos = pos
+			}
+		}
+	}
+
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// ReadFrom replaces the content of the index with an index read from r. It
+// implements io.ReaderFrom. Corrupt data returns an error wrapping ErrBadIndex.
+func (ix *Index) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	decoded := NewIndex(ix.tokenizer)
+	if err := decoded.decode(data); err != nil {
+		return int64(len(data)), fmt.Errorf("%w: %s", ErrBadIndex, err)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.docs, ix.postings, ix.totalLen = decoded.docs, decoded.postings, decoded.totalLen
+	return int64(len(data)), nil
+}
+
+func (ix *Index) decode(data []byte) error {
+	const sumSize = 4
+	if len(data) < len(indexMagic)+sumSize {
+		return fmt.Errorf("file too short")
+	}
+	body := data[:len(data)-sumSize]
+	if sum := binary.LittleEndian.Uint32(data[len(body):]); sum != crc32.ChecksumIEEE(body) {
+		return fmt.Errorf("checksum mismatch")
+	}
+	if !bytes.HasPrefix(body, []byte(indexMagic)) {
+		return fmt.Errorf("bad magic")
+	}
+
+	d := decoder{data: body[len(indexMagic):]}
+	if version := d.uvarint(); d.err == nil && version != indexVersion {
+		return fmt.Errorf("unsupported version %d", version)
+	}
+
+	numDocs := d.count()
+	ids := make([]string, 0, numDocs)
+	for i := 0; i < numDocs && d.err == nil; i++ {
+		id := string(d.bytes(d.count()))
+		if i > 0 && id <= ids[i-1] {
+			d.fail("documents not sorted")
+		}
+		doc := &docInfo{length: d.count()}
+		doc.gaps = d.int(math.MaxInt32 - doc.length)
+		ids = append(ids, id)
+		ix.docs[id] = doc
+		ix.totalLen += doc.length
+	}
+
+	numTerms := d.count()
+	prev := ""
+	for i := 0; i < numTerms && d.err == nil; i++ {
+		shared := d.int(len(prev))
+		term := prev[:shared] + string(d.bytes(d.count()))
+		if i > 0 && term <= prev {
+			d.fail("terms not sorted")
+		}
+		prev = term
+
+		numPostings := d.count()
+		docs := make(map[string][]int, numPostings)
+		num := 0
+		for j := 0; j < numPostings && d.err == nil; j++ {
+			delta := d.int(len(ids))
+			if j > 0 && delta == 0 {
+				d.fail("duplicate document")
+			}
+			num += delta
+			if num >= len(ids) {
+				d.fail("bad document number")
+				break
+			}
+			doc := ix.docs[ids[num]]
+
+			numPositions := d.count()
+			if numPositions == 0 {
+				d.fail("empty posting")
+			}
+			positions := make([]int, 0, numPositions)
+			pos := 0
+			for k := 0; k < numPositions && d.err == nil; k++ {
+				delta := d.int(doc.length + doc.gaps)
+				if k > 0 && delta == 0 {
+					d.fail("duplicate position")
+				}
+				pos += delta
+				if pos >= doc.length+doc.gaps {
+					d.fail("bad position")
+				}
+				positions = append(positions, pos)
+			}
+			docs[ids[num]] = positions
+			doc.terms = append(doc.terms, term)
+		}
+		if len(docs) > 0 {
+			ix.postings[term] = docs
+		}
+	}
+
+	if d.err == nil && len(d.data) > 0 {
+		d.fail("trailing data")
+	}
+	return d.err
+}
+
+// decoder decodes index and tagger data, the first error is kept in err and
+// all following calls return zero values.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail(msg string) {
+	if d.err == nil {
+		d.err = errors.New(msg)
+	}
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("bad varint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// int returns a number that can't be more than max.
+func (d *decoder) int(max int) int {
+	v := d.uvarint()
+	if v > uint64(max) {
+		d.fail("number out of range")
+		return 0
+	}
+	return int(v)
+}
+
+// count returns a count or a length, it can't be more than the number of
+// bytes left since every item takes at least one byte.
+func (d *decoder) count() int {
+	v := d.uvarint()
+	if v > uint64(len(d.data)) {
+		d.fail("count out of range")
+		return 0
+	}
+	return int(v)
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if n > len(d.data) {
+		d.fail("unexpected end of data")
+	}
+	if d.err != nil {
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
@@ -0,0 +1,122 @@
+package nlp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexWriteRead(t *testing.T) {
+	ix := newTestIndex(t)
+	var buf bytes.Buffer
+	n, err := ix.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+	data := buf.Bytes()
+
+	ix2 := NewIndex(ix.tokenizer)
+	n, err = ix2.ReadFrom(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), n)
+	require.Equal(t, ix.Len(), ix2.Len())
+
+	for _, ranking := range []Ranking{BM25, TFIDF} {
+		for _, tc := range searchCases {
+			expected, err := ix.Search(tc.query, ranking)
+			require.NoError(t, err)
+			results, err := ix2.Search(tc.query, ranking)
+			require.NoError(t, err)
+			require.Equal(t, expected, results, tc.query)
+		}
+	}
+
+	// Same index, same bytes
+	var buf2 bytes.Buffer
+	_, err = ix2.WriteTo(&buf2)
+	require.NoError(t, err)
+	require.Equal(t, data, buf2.Bytes())
+
+	// Read index can be modified
+	require.True(t, ix2.Remove("violin"))
+	ix2.Add("hound", "The Hound of the Baskervilles")
+	results, err := ix2.Search("hound", BM25)
+	require.NoError(t, err)
+	require.Equal(t, []string{"hound"}, resultIDs(results))
+}
+
+func TestIndexReadCorrupt(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := newTestIndex(t).WriteTo(&buf)
+	require.NoError(t, err)
+	data := buf.Bytes()
+
+	ix := NewIndex(nil)
+	for i := 0; i < len(data); i++ {
+		_, err := ix.ReadFrom(bytes.NewReader(data[:i]))
+		require.ErrorIs(t, err, ErrBadIndex, "truncated at %d", i)
+
+		corrupt := append([]byte(nil), data...)
+		corrupt[i] ^= 0x40
+		_, err = ix.ReadFrom(bytes.NewReader(corrupt))
+		require.ErrorIs(t, err, ErrBadIndex, "flipped byte %d", i)
+	}
+	require.Equal(t, 0, ix.Len())
+}
+
+func TestIndexReadPositions(t *testing.T) {
+	// document "a" with 1 token and 1 gap, term "x" at pos
+	indexData := func(pos byte) []byte {
+		data := append([]byte(indexMagic), 1, 1, 1, 'a', 1, 1, 1, 0, 1, 'x', 1, 0, 1, pos)
+		return binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	}
+
+	ix := NewIndex(nil)
+	_, err := ix.ReadFrom(bytes.NewReader(indexData(1)))
+	require.NoError(t, err)
+	require.Equal(t, 1, ix.Len())
+
+	_, err = ix.ReadFrom(bytes.NewReader(indexData(2)))
+	require.ErrorIs(t, err, ErrBadIndex)
+}
+
+func FuzzIndexReadFrom(f *testing.F) {
+	ix := NewIndex(nil)
+	ix.Add("1", "Holmes played the violin")
+	ix.Add("2", "Watson took the train")
+	var buf bytes.Buffer
+	if _, err := ix.WriteTo(&buf); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buf.Bytes())
+	f.Add([]byte(indexMagic))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkIndexData(t, data)
+
+		// Fix the checksum to get past it to the decoder
+		if len(data) > 4 {
+			body := data[:len(data)-4]
+			checkIndexData(t, binary.LittleEndian.AppendUint32(body, crc32.ChecksumIEEE(body)))
+		}
+	})
+}
+
+// checkIndexData checks that data is either rejected or is a usable index.
+func checkIndexData(t *testing.T, data []byte) {
+	ix := NewIndex(nil)
+	if _, err := ix.ReadFrom(bytes.NewReader(data)); err != nil {
+		require.ErrorIs(t, err, ErrBadIndex)
+		return
+	}
+
+	_, err := ix.Search("holmes OR train", BM25)
+	require.NoError(t, err)
+	ix.Remove("1")
+	var out bytes.Buffer
+	_, err = ix.WriteTo(&out)
+	require.NoError(t, err)
+}
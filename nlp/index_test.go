@@ -0,0 +1,110 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var indexDocs = map[string]string{
+	"scandal":  "A Scandal in Bohemia. To Sherlock Holmes she is always the woman.",
+	"league":   "The Red-Headed League. Sherlock Holmes listened to the story of the red-headed man.",
+	"identity": "A Case of Identity. My dear fellow, said Sherlock Holmes, life is infinitely stranger.",
+	"boscombe": "The Boscombe Valley Mystery. Holmes and Watson took the train to Boscombe.",
+	"violin":   "Holmes played the violin. Watson listened. Holmes played the violin again.",
+}
+
+func newTestIndex(t *testing.T) *Index {
+	sw, err := BuiltinStopWords("en")
+	require.NoError(t, err)
+	tok, err := NewTokenizer(WithStopWords(sw))
+	require.NoError(t, err)
+
+	ix := NewIndex(tok)
+	for id, text := range indexDocs {
+		ix.Add(id, text)
+	}
+	return ix
+}
+
+func resultIDs(results []Result) []string {
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+var searchCases = []struct {
+	query string
+	ids   []string
+}{
+	{"bohemia", []string{"scandal"}},
+	{"Sherlock Holmes", []string{"identity", "league", "scandal"}},
+	{"sherlock AND listened", []string{"league"}},
+	{"valley OR violin", []string{"boscombe", "violin"}},
+	{"holmes NOT sherlock", []string{"boscombe", "violin"}},
+	{"NOT holmes", nil},
+	{"watson AND (train OR violin)", []string{"boscombe", "violin"}},
+	{`"red-headed league"`, []string{"league"}},
+	{`"headed red"`, nil},
+	{`"played the violin"`, []string{"violin"}},
+	{`"played a violin"`, []string{"violin"}},
+	{`"played violin"`, nil},
+	{`"bohemia sherlock"`, nil}, // "Bohemia. To Sherlock", "to" is a stop word
+	{"the", nil},
+	{"", nil},
+	{"moriarty", nil},
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := newTestIndex(t)
+	require.Equal(t, len(indexDocs), ix.Len())
+
+	for _, ranking := range []Ranking{BM25, TFIDF} {
+		for _, tc := range searchCases {
+			t.Run(tc.query, func(t *testing.T) {
+				results, err := ix.Search(tc.query, ranking)
+				require.NoError(t, err)
+				require.ElementsMatch(t, tc.ids, resultIDs(results))
+			})
+		}
+	}
+}
+
+func TestIndexRanking(t *testing.T) {
+	ix := newTestIndex(t)
+	for _, ranking := range []Ranking{BM25, TFIDF} {
+		results, err := ix.Search("violin OR train", ranking)
+		require.NoError(t, err)
+		require.Equal(t, []string{"violin", "boscombe"}, resultIDs(results))
+		require.Greater(t, results[0].Score, results[1].Score)
+	}
+}
+
+func TestIndexRemove(t *testing.T) {
+	ix := newTestIndex(t)
+	require.True(t, ix.Remove("violin"))
+	require.False(t, ix.Remove("violin"))
+	require.Equal(t, len(indexDocs)-1, ix.Len())
+
+	results, err := ix.Search("violin", BM25)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// Add replaces
+	ix.Add("league", "Moriarty")
+	results, err = ix.Search("moriarty OR listened", BM25)
+	require.NoError(t, err)
+	require.Equal(t, []string{"league"}, resultIDs(results))
+}
+
+func TestIndexBadQuery(t *testing.T) {
+	ix := newTestIndex(t)
+	for _, query := range []string{`"holmes`, "(holmes", "holmes)", "holmes OR", "NOT", "()"} {
+		t.Run(query, func(t *testing.T) {
+			_, err := ix.Search(query, BM25)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Command traintagger trains a part-of-speech tagger from a tagged corpus
+// (see nlp.ReadTaggedSentences) and writes it to a tagger file.
+//
+//	traintagger -o en.model en.txt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/osshu320/nlp"
+)
+
+func main() {
+	log.SetFlags(0)
+	out := flag.String("o", "", "output file (required)")
+	iterations := flag.Int("n", 10, "number of training iterations")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: traintagger -o model corpus")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *out == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := train(flag.Arg(0), *out, *iterations); err != nil {
+		log.Fatalf("error: %s", err)
+	}
+}
+
+func train(corpus, out string, iterations int) error {
+	file, err := os.Open(corpus)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	sentences, err := nlp.ReadTaggedSentences(file)
+	if err != nil {
+		return fmt.Errorf("%s:%w", corpus, err)
+	}
+	tg, err := nlp.TrainTagger(sentences, iterations)
+	if err != nil {
+		return err
+	}
+
+	model, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if _, err := tg.WriteTo(model); err != nil {
+		model.Close()
+		return err
+	}
+	return model.Close()
+}
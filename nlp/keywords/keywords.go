@@ -0,0 +1,133 @@
+// Package keywords extracts keyphrases from documents with RAKE and TextRank.
+// Keyphrases are returned in their surface form, as found in the document,
+// but are compared by their tokens, so "Red Circle" and "red circles" are
+// the same keyphrase.
+package keywords
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+
+	"github.com/osshu320/nlp"
+)
+
+// Keyphrase is a ranked keyphrase.
+type Keyphrase struct {
+	Text  string // Surface form, as first found in the document
+	Score float64
+}
+
+// Options are keyphrase extraction options.
+type Options struct {
+	// Tokenizer splits the document to tokens, it must not drop stop words.
+	// If nil, the default (English) tokenizer is used.
+	Tokenizer *nlp.Tokenizer
+	// StopWords split phrases and are never keywords. If nil, the built-in
+	// English stop words are used.
+	StopWords nlp.StopWords
+	// MaxWords is the maximal number of words in a keyphrase, 0 means 3.
+	MaxWords int
+	// Window is the co-occurrence window size for TextRank, 0 means 2
+	// (adjacent candidate words).
+	Window int
+	// Tagger restricts TextRank keywords to nouns and adjectives (NOUN, PROPN
+	// and ADJ tags). If nil, all words that are not stop words are
+	// candidates.
+	Tagger *nlp.Tagger
+}
+
+// word is a token of the document.
+type word struct {
+	nlp.Token
+	stop  bool // stop word (or not a word)
+	split bool // a phrase can't continue from the previous word to this one
+}
+
+func (o Options) maxWords() int {
+	if o.MaxWords <= 0 {
+		return 3
+	}
+	return o.MaxWords
+}
+
+// words returns the tokens of text.
+func (o Options) words(text string) []word {
+	tok := o.Tokenizer
+	if tok == nil {
+		tok = defaultTokenizer
+	}
+	stopWords := o.StopWords
+	if stopWords == nil {
+		stopWords = englishStopWords
+	}
+
+	fold := cases.Fold()
+	tokens := tok.TokenizeWithOffsets(text)
+	words := make([]word, len(tokens))
+	for i, t := range tokens {
+		words[i] = word{
+			Token: t,
+			stop:  t.Type != nlp.WordToken || stopWords.Contains(fold.String(t.Text)),
+			split: i > 0 && isDelimiter(text[tokens[i-1].End:t.Start]),
+		}
+	}
+	return words
+}
+
+var (
+	defaultTokenizer, _ = nlp.NewTokenizer()
+	englishStopWords, _ = nlp.BuiltinStopWords("en")
+)
+
+// isDelimiter reports if the text between two tokens splits phrases: it has
+// punctuation other than hyphens ("red-headed") or a paragraph break.
+func isDelimiter(gap string) bool {
+	if strings.Count(gap, "\n") > 1 {
+		return true
+	}
+	for _, r := range gap {
+		if !unicode.IsSpace(r) && r != '-' {
+			return true
+		}
+	}
+	return false
+}
+
+// phrase is a keyphrase candidate: words[start:end].
+type phrase struct {
+	start, end int
+}
+
+// key returns the normalized form of p, used to compare phrases.
+func (p phrase) key(words []word) string {
+	norms := make([]string, 0, p.end-p.start)
+	for _, w := range words[p.start:p.end] {
+		norms = append(norms, w.Norm)
+	}
+	return strings.Join(norms, " ")
+}
+
+// rank returns the distinct phrases, scored by score, best first.
+func rank(text string, words []word, phrases []phrase, score func(phrase) float64) []Keyphrase {
+	seen := make(map[string]bool)
+	var keyphrases []Keyphrase
+	for _, p := range phrases {
+		key := p.key(words)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keyphrases = append(keyphrases, Keyphrase{
+			Text:  text[words[p.start].Start:words[p.end-1].End],
+			Score: score(p),
+		})
+	}
+
+	sort.SliceStable(keyphrases, func(i, j int) bool {
+		return keyphrases[i].Score > keyphrases[j].Score
+	})
+	return keyphrases
+}
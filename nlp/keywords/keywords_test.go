@@ -0,0 +1,92 @@
+package keywords
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osshu320/nlp"
+)
+
+// From the abstract of Rose et al, "Automatic Keyword Extraction from
+// Individual Documents" (2010).
+const rakeText = `Compatibility of systems of linear constraints over the set of natural numbers.
+Criteria of compatibility of a system of linear Diophantine equations, strict inequations,
+and nonstrict inequations are considered. Upper bounds for components of a minimal set of
+solutions and algorithms of construction of minimal generating sets of solutions for all
+types of systems are given. These criteria and the corresponding algorithms for
+constructing a minimal supporting set of solutions can be used in solving all the
+considered types of systems and systems of mixed types.`
+
+func keyphraseTexts(keyphrases []Keyphrase, n int) []string {
+	var texts []string
+	for i, k := range keyphrases {
+		if i == n {
+			break
+		}
+		texts = append(texts, k.Text)
+	}
+	return texts
+}
+
+func TestRAKE(t *testing.T) {
+	keyphrases := RAKE(rakeText, Options{})
+	expected := []string{"linear Diophantine equations", "minimal generating sets", "minimal supporting set", "minimal set"}
+	require.Equal(t, expected, keyphraseTexts(keyphrases, 4))
+	require.InDelta(t, 8.5, keyphrases[0].Score, 1e-9)
+
+	for _, k := range RAKE(rakeText, Options{MaxWords: 2}) {
+		require.LessOrEqual(t, len(strings.Fields(k.Text)), 2, k.Text)
+	}
+}
+
+func TestRAKEPhrases(t *testing.T) {
+	text := "The Red Circle, and the red circles! A red-headed league; of\n\nthe Red"
+	keyphrases := RAKE(text, Options{})
+	require.Equal(t, []string{"red-headed league", "Red Circle", "Red"}, keyphraseTexts(keyphrases, -1))
+
+	sw, err := nlp.BuiltinStopWords("en")
+	require.NoError(t, err)
+	sw["circle"], sw["circles"] = true, true
+	keyphrases = RAKE(text, Options{StopWords: sw})
+	require.Equal(t, []string{"red-headed league", "Red"}, keyphraseTexts(keyphrases, -1))
+
+	require.Empty(t, RAKE("", Options{}))
+	require.Empty(t, RAKE("the and of", Options{}))
+}
+
+func TestTextRank(t *testing.T) {
+	keyphrases := TextRank(rakeText, Options{})
+	require.Equal(t, []string{"minimal set", "considered types"}, keyphraseTexts(keyphrases, 2))
+	for i := 1; i < len(keyphrases); i++ {
+		require.GreaterOrEqual(t, keyphrases[i-1].Score, keyphrases[i].Score)
+	}
+	require.Equal(t, keyphrases, TextRank(rakeText, Options{}), "not deterministic")
+
+	require.Nil(t, TextRank("", Options{}))
+	require.Nil(t, TextRank("the and of", Options{}))
+}
+
+func TestTextRankTagger(t *testing.T) {
+	text := "Holmes quickly examined the muddy boots. The muddy boots were wet and Holmes smiled."
+	keyphrases := TextRank(text, Options{Tagger: nlp.EnglishTagger()})
+	for _, k := range keyphrases {
+		require.NotContains(t, []string{"quickly", "examined", "smiled"}, k.Text)
+	}
+	require.Equal(t, []string{"Holmes", "boots"}, keyphraseTexts(keyphrases, -1))
+}
+
+func ExampleRAKE() {
+	text := "Sherlock Holmes was reading the advertisement of the Red-Headed League. " +
+		"The league offered four pounds a week to a man with red hair."
+	for _, k := range RAKE(text, Options{})[:3] {
+		fmt.Printf("%s %.2f\n", k.Text, k.Score)
+	}
+
+	// Output:
+	// Red-Headed League 8.50
+	// red hair 4.50
+	// Sherlock Holmes 4.00
+}
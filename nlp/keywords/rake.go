@@ -0,0 +1,43 @@
+package keywords
+
+// RAKE returns the keyphrases of text, best first, using Rapid Automatic
+// Keyword Extraction (Rose et al, 2010). Candidate keyphrases are sequences
+// of words delimited by stop words and punctuation. The score of a word is
+// its degree (the number of words in the candidates it appears in) divided
+// by its frequency, the score of a keyphrase is the sum of its word scores.
+// Candidates longer than opts.MaxWords are dropped.
+func RAKE(text string, opts Options) []Keyphrase {
+	words := opts.words(text)
+
+	var phrases []phrase
+	start := 0
+	for i := 0; i <= len(words); i++ {
+		if i < len(words) && !words[i].stop && (i == start || !words[i].split) {
+			continue
+		}
+		if i > start && i-start <= opts.maxWords() {
+			phrases = append(phrases, phrase{start, i})
+		}
+		start = i
+		if i < len(words) && words[i].stop {
+			start++
+		}
+	}
+
+	degree := make(map[string]int) // word -> degree
+	freq := make(map[string]int)   // word -> frequency
+	for _, p := range phrases {
+		for _, w := range words[p.start:p.end] {
+			degree[w.Norm] += p.end - p.start
+			freq[w.Norm]++
+		}
+	}
+
+	return rank(text, words, phrases, func(p phrase) float64 {
+		score := 0.0
+		for _, w := range words[p.start:p.end] {
+			score += float64(degree[w.Norm]) / float64(freq[w.Norm])
+		}
+		return score
+	})
+}
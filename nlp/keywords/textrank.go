@@ -0,0 +1,166 @@
+package keywords
+
+import (
+	"math"
+	"sort"
+
+	"github.com/osshu320/nlp"
+)
+
+// TextRank parameters
+const (
+	damping       = 0.85
+	maxIterations = 100
+	tolerance     = 1e-6
+)
+
+// TextRank returns the keyphrases of text, best first, using TextRank
+// (Mihalcea & Tarau, 2004). Words that are not stop words (only nouns and
+// adjectives if opts.Tagger is set) are vertices in a graph, linked when
+// they appear within opts.Window candidate words of each other. Words are
+// ranked with PageRank, the top third are keywords, and sequences of
+// keywords in text are merged to keyphrases (up to opts.MaxWords words).
+// The score of a keyphrase is the sum of its word ranks.
+func TextRank(text string, opts Options) []Keyphrase {
+	words := opts.words(text)
+	if opts.Tagger != nil {
+		tags := opts.Tagger.Tag(tokens(words))
+		for i, tag := range tags {
+			if tag != "NOUN" && tag != "PROPN" && tag != "ADJ" {
+				words[i].stop = true
+			}
+		}
+	}
+
+	ranks := pageRank(cooccurrences(words, opts.Window))
+	if len(ranks) == 0 {
+		return nil
+	}
+
+	// top third of the words are keywords
+	terms := make([]string, 0, len(ranks))
+	for term := range ranks {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if ranks[terms[i]] != ranks[terms[j]] {
+			return ranks[terms[i]] > ranks[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	keywords := make(map[string]bool)
+	for _, term := range terms[:(len(terms)+2)/3] {
+		keywords[term] = true
+	}
+
+	// merge adjacent keywords
+	var phrases []phrase
+	isKeyword := func(i int) bool { return !words[i].stop && keywords[words[i].Norm] }
+	for i := 0; i < len(words); {
+		if !isKeyword(i) {
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(words) && end-i < opts.maxWords() && isKeyword(end) && !words[end].split {
+			end++
+		}
+		phrases = append(phrases, phrase{i, end})
+		i = end
+	}
+
+	return rank(text, words, phrases, func(p phrase) float64 {
+		score := 0.0
+		for _, w := range words[p.start:p.end] {
+			score += ranks[w.Norm]
+		}
+		return score
+	})
+}
+
+func tokens(words []word) []nlp.Token {
+	toks := make([]nlp.Token, len(words))
+	for i, w := range words {
+		toks[i] = w.Token
+	}
+	return toks
+}
+
+// cooccurrences returns the co-occurrence graph of the candidate words:
+// word -> word -> number of co-occurrences.
+func cooccurrences(words []word, window int) map[string]map[string]float64 {
+	if window <= 0 {
+		window = 2
+	}
+
+	graph := make(map[string]map[string]float64)
+	var candidates []string
+	for _, w := range words {
+		if w.stop {
+			continue
+		}
+		candidates = append(candidates, w.Norm)
+		if graph[w.Norm] == nil {
+			graph[w.Norm] = make(map[string]float64)
+		}
+	}
+
+	for i, a := range candidates {
+		for j := i + 1; j < len(candidates) && j < i+window; j++ {
+			b := candidates[j]
+			if a != b {
+				graph[a][b]++
+				graph[b][a]++
+			}
+		}
+	}
+	return graph
+}
+
+// pageRank returns the (weighted) PageRank of the vertices of graph.
+func pageRank(graph map[string]map[string]float64) map[string]float64 {
+	// sorted vertices, so results don't depend on map order
+	vertices := make([]string, 0, len(graph))
+	for v := range graph {
+		vertices = append(vertices, v)
+	}
+	sort.Strings(vertices)
+
+	outWeight := make(map[string]float64, len(graph))
+	for v, edges := range graph {
+		for _, w := range edges {
+			outWeight[v] += w
+		}
+	}
+
+	ranks := make(map[string]float64, len(graph))
+	for _, v := range vertices {
+		ranks[v] = 1
+	}
+	for iter := 0; iter < maxIterations; iter++ {
+		next := make(map[string]float64, len(graph))
+		delta := 0.0
+		for _, v := range vertices {
+			sum := 0.0
+			for _, u := range sortedKeys(graph[v]) {
+				sum += graph[v][u] / outWeight[u] * ranks[u]
+			}
+			next[v] = 1 - damping + damping*sum
+			delta = math.Max(delta, math.Abs(next[v]-ranks[v]))
+		}
+		ranks = next
+		if delta < tolerance {
+			break
+		}
+	}
+	return ranks
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
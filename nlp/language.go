@@ -0,0 +1,156 @@
+package nlp
+
+import (
+	"bufio"
+	"embed"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Language profiles are built from the sample texts in languages/, one file
+// per language named by its ISO 639-1 code. The texts were written for this
+// package, on the same topics in every language, and are in the public domain
+// like the rest of it.
+
+//go:embed languages/*.txt
+var languagesFS embed.FS
+
+// LanguageScore is a candidate language of a text.
+type LanguageScore struct {
+	Language   string  // ISO 639-1 code (e.g. "en")
+	Confidence float64 // Relative score between 0 and 1, the scores of all candidates sum to 1
+}
+
+// langProfile is the character trigram counts of a language.
+type langProfile struct {
+	lang   string
+	counts map[string]int
+	total  int
+}
+
+var (
+	langProfilesOnce sync.Once
+	langProfiles     []*langProfile
+	langVocabSize    int // number of distinct trigrams in all profiles
+)
+
+func loadLangProfiles() {
+	entries, err := languagesFS.ReadDir("languages")
+	if err != nil {
+		panic(err) // embedded, can't happen
+	}
+
+	vocab := make(map[string]bool)
+	for _, entry := range entries {
+		data, err := languagesFS.ReadFile("languages/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		p := &langProfile{
+			lang:   strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())),
+			counts: make(map[string]int),
+		}
+		s := bufio.NewScanner(strings.NewReader(string(data)))
+		for s.Scan() {
+			if strings.HasPrefix(s.Text(), "#") {
+				continue
+			}
+			trigrams(s.Text(), func(tri string) {
+				p.counts[tri]++
+				p.total++
+				vocab[tri] = true
+			})
+		}
+		langProfiles = append(langProfiles, p)
+	}
+	langVocabSize = len(vocab) + 1 // +1 for unseen trigrams
+}
+
+// trigrams calls fn with the character trigrams of the words in text. Words
+// are lower cased and padded with a space on each side, so "Go" yields " go"
+// and "go ".
+func trigrams(text string, fn func(string)) {
+	rs := []rune{' '}
+	flush := func() {
+		if len(rs) > 1 {
+			rs = append(rs, ' ')
+			for i := 0; i+3 <= len(rs); i++ {
+				fn(string(rs[i : i+3]))
+			}
+		}
+		rs = rs[:1]
+	}
+
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) {
+			rs = append(rs, unicode.ToLower(r))
+			continue
+		}
+		flush()
+	}
+	flush()
+}
+
+// DetectLanguage returns the candidate languages of text, most likely first.
+// It uses a naive Bayes classifier over character trigrams. The confidences
+// are a softmax of the mean log-likelihood of a trigram of text under each
+// language profile: they rank the candidates and don't grow with the length
+// of text, but they're relative scores, not the probability that a language
+// is right. Short texts (a few words) are often misclassified. DetectLanguage returns nil if text has no letters
+// or if none of its trigrams is in a profile, e.g. text in an unsupported
+// script ("東京").
+//
+// The supported languages are: Dutch (nl), English (en), Finnish (fi), French
+// (fr), German (de), Italian (it), Polish (pl), Portuguese (pt), Russian
+// (ru), Spanish (es), Swedish (sv) and Turkish (tr).
+func DetectLanguage(text string) []LanguageScore {
+	langProfilesOnce.Do(loadLangProfiles)
+
+	logProbs := make([]float64, len(langProfiles))
+	known := false // a trigram of text is in a profile
+	n := 0         // number of trigrams
+	trigrams(text, func(tri string) {
+		n++
+		for i, p := range langProfiles {
+			count := p.counts[tri]
+			if count > 0 {
+				known = true
+			}
+			// add-one smoothing
+			logProbs[i] += math.Log(float64(count+1) / float64(p.total+langVocabSize))
+		}
+	})
+	if !known {
+		return nil
+	}
+
+	best := math.Inf(-1)
+	for _, lp := range logProbs {
+		if lp > best {
+			best = lp
+		}
+	}
+	total := 0.0
+	scores := make([]LanguageScore, len(langProfiles))
+	for i, p := range langProfiles {
+		// softmax of the mean, shifted to avoid underflow
+		prob := math.Exp((logProbs[i] - best) / float64(n))
+		scores[i] = LanguageScore{p.lang, prob}
+		total += prob
+	}
+	for i := range scores {
+		scores[i].Confidence /= total
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Confidence != scores[j].Confidence {
+			return scores[i].Confidence > scores[j].Confidence
+		}
+		return scores[i].Language < scores[j].Language
+	})
+	return scores
+}
@@ -0,0 +1,70 @@
+package nlp
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type languageCase struct {
+	Text     string
+	Language string
+}
+
+func loadLanguageCases(t *testing.T) []languageCase {
+	data, err := os.ReadFile("language_cases.toml")
+	require.NoError(t, err)
+
+	var testCases struct {
+		Cases []languageCase
+	}
+
+	err = toml.Unmarshal(data, &testCases)
+	require.NoError(t, err, "Unmarshal TOML")
+	return testCases.Cases
+}
+
+func TestDetectLanguage(t *testing.T) {
+	for _, tc := range loadLanguageCases(t) {
+		t.Run(tc.Language, func(t *testing.T) {
+			scores := DetectLanguage(tc.Text)
+			require.Len(t, scores, 12)
+			require.Equal(t, tc.Language, scores[0].Language)
+
+			total := 0.0
+			for i, s := range scores {
+				if i > 0 {
+					require.LessOrEqual(t, s.Confidence, scores[i-1].Confidence)
+				}
+				total += s.Confidence
+			}
+			require.InDelta(t, 1.0, total, 1e-9)
+		})
+	}
+}
+
+func TestDetectLanguageLength(t *testing.T) {
+	// Confidences don't grow with the length of the text
+	text := loadLanguageCases(t)[0].Text
+	scores := DetectLanguage(text)
+	require.Less(t, scores[0].Confidence, 0.9)
+	long := DetectLanguage(strings.Repeat(text+" ", 20))
+	for i, s := range scores {
+		require.Equal(t, s.Language, long[i].Language)
+		require.InDelta(t, s.Confidence, long[i].Confidence, 1e-9)
+	}
+}
+
+func TestDetectLanguageNoLetters(t *testing.T) {
+	require.Nil(t, DetectLanguage(""))
+	require.Nil(t, DetectLanguage("3.14 -- 42!"))
+}
+
+func TestDetectLanguageUnknownScript(t *testing.T) {
+	require.Nil(t, DetectLanguage("東京"))
+	require.Nil(t, DetectLanguage("東京 2024"))
+	require.NotNil(t, DetectLanguage("東京 is big"))
+}
@@ -0,0 +1,148 @@
+// Package lemmatizer reduces English words to their dictionary form (lemma).
+package lemmatizer
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+)
+
+var (
+	//go:embed lemmas.txt
+	lemmasData string
+
+	// lemmas is form -> lemma
+	lemmas = loadLemmas(lemmasData)
+
+	// known are the lemmas of the table, ambiguous suffixes are only removed
+	// when the result is known ("agreed" -> "agree" but "proceed").
+	known = knownLemmas(lemmas)
+)
+
+func loadLemmas(data string) map[string]string {
+	m := make(map[string]string)
+	s := bufio.NewScanner(strings.NewReader(data))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		m[fields[0]] = fields[1]
+	}
+	return m
+}
+
+func knownLemmas(lemmas map[string]string) map[string]bool {
+	m := make(map[string]bool, len(lemmas))
+	for _, lemma := range lemmas {
+		m[lemma] = true
+	}
+	return m
+}
+
+// Lemmatize returns the dictionary form of a lower case English word
+// ("ran" -> "run", "mice" -> "mouse", "studies" -> "study").
+// Irregular forms are looked up in a table, regular inflections are removed
+// by rules.
+func Lemmatize(word string) string {
+	word = strings.TrimSuffix(word, "'s")
+	word = strings.TrimSuffix(word, "'")
+	if lemma, ok := lemmas[word]; ok {
+		return lemma
+	}
+	if len(word) <= 3 {
+		return word
+	}
+
+	switch {
+	// plurals & 3rd person
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case hasAnySuffix(word, "ches", "shes", "xes", "zzes"):
+		return word[:len(word)-2]
+	case hasAnySuffix(word, "ses", "oes") && known[word[:len(word)-2]]: // "gases", "heroes"
+		return word[:len(word)-2]
+	case hasAnySuffix(word, "ss", "us", "is"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+
+	// past & continuous
+	case strings.HasSuffix(word, "ied") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "eed"):
+		if known[word[:len(word)-1]] { // "agreed"
+			return word[:len(word)-1]
+		}
+		return word // "need", "proceed"
+	case strings.HasSuffix(word, "ed"):
+		return verbStem(word, word[:len(word)-2])
+	case strings.HasSuffix(word, "ing"):
+		return verbStem(word, word[:len(word)-3])
+	}
+	return word
+}
+
+// verbStem returns the lemma of a verb form given its stem after the "ed" or
+// "ing" suffix was removed.
+func verbStem(word, stem string) string {
+	if !strings.ContainsAny(stem, "aeiouy") { // "thing", "shed"
+		return word
+	}
+
+	n := len(stem)
+	switch {
+	case isDouble(stem) && n > 3: // "stopped", "running"
+		return stem[:n-1]
+	case hasAnySuffix(stem, "at", "bl", "iz", "us", "c", "v", "z", "dg", "rg"): // "created", "loved"
+		return stem + "e"
+	case isShort(stem): // "hoped", "making"
+		return stem + "e"
+	}
+	return stem
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) != -1
+}
+
+// isDouble reports if s ends with a double consonant that is undoubled in
+// inflections ("ll", "ss" & "zz" are not: "called", "missed").
+func isDouble(s string) bool {
+	n := len(s)
+	if n < 2 || s[n-1] != s[n-2] {
+		return false
+	}
+	return strings.IndexByte("bdfgmnprt", s[n-1]) != -1
+}
+
+// isShort reports if s has a single syllable ending with consonant, vowel,
+// consonant (not w, x or y).
+func isShort(s string) bool {
+	n := len(s)
+	if n < 3 || strings.IndexByte("wxy", s[n-1]) != -1 {
+		return false
+	}
+	if isVowel(s[n-1]) || !isVowel(s[n-2]) || isVowel(s[n-3]) {
+		return false
+	}
+
+	syllables := 0
+	for i := 0; i < n; i++ {
+		if isVowel(s[i]) && (i == 0 || !isVowel(s[i-1])) {
+			syllables++
+		}
+	}
+	return syllables == 1
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package lemmatizer
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLemmatize(t *testing.T) {
+	file, err := os.Open("testdata/lemmas.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	s := bufio.NewScanner(file)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		word, lemma := fields[0], fields[1]
+		t.Run(word, func(t *testing.T) {
+			require.Equal(t, lemma, Lemmatize(word))
+		})
+	}
+	require.NoError(t, s.Err())
+}
@@ -0,0 +1,64 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+)
+
+// NGramOptions are options for NGrams.
+type NGramOptions struct {
+	MinN      int    // Minimal number of tokens in an n-gram (default 2)
+	MaxN      int    // Maximal number of tokens in an n-gram (default MinN)
+	Separator string // Separator between tokens (default " ")
+
+	// If StopWords is set, n-grams starting or ending with a stop word are
+	// skipped ("bank of england" is kept, "of england" is not). Tokens are
+	// compared as is, use a Tokenizer without stemming to get surface forms.
+	StopWords StopWords
+}
+
+// NGrams returns the n-grams of tokens, ordered by size and then by position.
+func NGrams(tokens []string, opts NGramOptions) []string {
+	minN, maxN := opts.MinN, opts.MaxN
+	if minN <= 0 {
+		minN = 2
+	}
+	if maxN < minN {
+		maxN = minN
+	}
+	sep := opts.Separator
+	if sep == "" {
+		sep = " "
+	}
+
+	var ngrams []string
+	for n := minN; n <= maxN; n++ {
+		for i := 0; i+n <= len(tokens); i++ {
+			gram := tokens[i : i+n]
+			if opts.StopWords.Contains(gram[0]) || opts.StopWords.Contains(gram[n-1]) {
+				continue
+			}
+			ngrams = append(ngrams, strings.Join(gram, sep))
+		}
+	}
+	return ngrams
+}
+
+// Shingles returns the character k-shingles (substrings of k runes) of text,
+// in order. text is case folded and runs of white space are replaced by a
+// single space before splitting.
+func Shingles(text string, k int) []string {
+	if k <= 0 {
+		return nil
+	}
+
+	text = cases.Fold().String(strings.Join(strings.FieldsFunc(text, unicode.IsSpace), " "))
+	runes := []rune(text)
+	var shingles []string
+	for i := 0; i+k <= len(runes); i++ {
+		shingles = append(shingles, string(runes[i:i+k]))
+	}
+	return shingles
+}
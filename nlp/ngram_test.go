@@ -0,0 +1,78 @@
+package nlp
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type ngramCase struct {
+	Text      string
+	Language  string
+	StopWords bool `toml:"stop_words"` // skip n-grams with English stop words at edges
+	Min       int
+	Max       int
+	Separator string
+	NGrams    []string
+}
+
+type shingleCase struct {
+	Text     string
+	K        int
+	Shingles []string
+}
+
+func loadNGramCases(t *testing.T) ([]ngramCase, []shingleCase) {
+	data, err := os.ReadFile("ngram_cases.toml")
+	require.NoError(t, err)
+
+	var testCases struct {
+		NGrams   []ngramCase
+		Shingles []shingleCase
+	}
+
+	err = toml.Unmarshal(data, &testCases)
+	require.NoError(t, err, "Unmarshal TOML")
+	return testCases.NGrams, testCases.Shingles
+}
+
+func TestNGramsTable(t *testing.T) {
+	ngramCases, _ := loadNGramCases(t)
+	for _, tc := range ngramCases {
+		t.Run(tc.Text, func(t *testing.T) {
+			opts := NGramOptions{
+				MinN:      tc.Min,
+				MaxN:      tc.Max,
+				Separator: tc.Separator,
+			}
+			if tc.StopWords {
+				sw, err := BuiltinStopWords("en")
+				require.NoError(t, err)
+				opts.StopWords = sw
+			}
+			tok := tokenizeCase{Language: tc.Language}.tokenizer(t)
+			ngrams := NGrams(tok.Tokenize(tc.Text), opts)
+			if len(tc.NGrams) == 0 {
+				require.Empty(t, ngrams)
+				return
+			}
+			require.Equal(t, tc.NGrams, ngrams) // by size, then by position
+		})
+	}
+}
+
+func TestShinglesTable(t *testing.T) {
+	_, shingleCases := loadNGramCases(t)
+	for _, tc := range shingleCases {
+		t.Run(tc.Text, func(t *testing.T) {
+			shingles := Shingles(tc.Text, tc.K)
+			if len(tc.Shingles) == 0 {
+				require.Empty(t, shingles)
+				return
+			}
+			require.Equal(t, tc.Shingles, shingles)
+		})
+	}
+}
@@ -1,26 +1,266 @@
 package nlp
 
 import (
-	"regexp"
+	"errors"
 	"strings"
+	"unicode/utf8"
 
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/osshu320/nlp/lemmatizer"
 	"github.com/osshu320/nlp/stemmer"
 )
 
 var (
-	wordRe = regexp.MustCompile(`[a-zA-Z]+`)
+	defaultTokenizer = &Tokenizer{stemmer: stemmer.Func(stemmer.Stem)}
+
+	// Typographic apostrophes are normalized to ' so "who’s" == "who's"
+	apostrophes = strings.NewReplacer("’", "'", "‘", "'", "ʼ", "'")
+
+	errLemmatizerStemmer = errors.New("WithLemmatizer can't be used with WithLanguage or WithStemmer")
 )
 
-// Tokenize returns list of (lower case) tokens found in text.
-func Tokenize(text string) []string {
-	words := wordRe.FindAllString(text, -1)
-	var tokens []string
-	for _, w := range words {
-		token := strings.ToLower(w)
-		token = stemmer.Stem(token)
-		if len(token) != 0 {
-			tokens = append(tokens, token)
+// Tokenizer splits text to normalized tokens.
+type Tokenizer struct {
+	stemmer      stemmer.Stemmer
+	lemmatize    bool
+	nfkc         bool
+	stopWords    StopWords
+	rules        []Rule
+	contractions bool
+}
+
+// Option is a Tokenizer option.
+type Option func(*Tokenizer) error
+
+// WithLanguage sets the stemmer to the one registered for lang (e.g. "fr").
+// Use "none" to disable stemming. It can't be used with WithLemmatizer.
+func WithLanguage(lang string) Option {
+	return func(t *Tokenizer) error {
+		if t.lemmatize {
+			return errLemmatizerStemmer
 		}
+		s, err := stemmer.ForLanguage(lang)
+		if err != nil {
+			return err
+		}
+		t.stemmer = s
+		return nil
+	}
+}
+
+// WithStemmer sets the stemmer. It can't be used with WithLemmatizer.
+func WithStemmer(s stemmer.Stemmer) Option {
+	return func(t *Tokenizer) error {
+		if t.lemmatize {
+			return errLemmatizerStemmer
+		}
+		t.stemmer = s
+		return nil
+	}
+}
+
+// WithLemmatizer reduces words to their dictionary form ("mice" -> "mouse")
+// instead of stemming them. Only English is supported, it can't be used with
+// WithLanguage or WithStemmer.
+func WithLemmatizer() Option {
+	return func(t *Tokenizer) error {
+		if t.stemmer != nil && !t.lemmatize {
+			return errLemmatizerStemmer
+		}
+		t.stemmer, t.lemmatize = stemmer.Func(lemmatizer.Lemmatize), true
+		return nil
+	}
+}
+
+// WithNFKC applies Unicode NFKC normalization to words ("ﬁ" -> "fi").
+func WithNFKC() Option {
+	return func(t *Tokenizer) error {
+		t.nfkc = true
+		return nil
+	}
+}
+
+// WithStopWords drops tokens that are in one of words.
+func WithStopWords(words ...StopWords) Option {
+	return func(t *Tokenizer) error {
+		if t.stopWords == nil {
+			t.stopWords = make(StopWords)
+		}
+		for _, w := range words {
+			t.stopWords.Merge(w)
+		}
+		return nil
+	}
+}
+
+// WithRules recognizes special tokens (URLs, hashtags ...) with rules, they
+// are tried in order before splitting text to words.
+//
+//	nlp.WithRules(nlp.URLRule, nlp.EmailRule, nlp.HashtagRule)
+func WithRules(rules ...Rule) Option {
+	return func(t *Tokenizer) error {
+		t.rules = append(t.rules, rules...)
+		return nil
+	}
+}
+
+// WithContractions expands English contractions to two tokens ("don't" ->
+// "do", "not"). The Text of the tokens is their part of the contraction
+// ("do" and "n't").
+func WithContractions() Option {
+	return func(t *Tokenizer) error {
+		t.contractions = true
+		return nil
+	}
+}
+
+// NewTokenizer returns a new Tokenizer, by default it uses the English stemmer.
+func NewTokenizer(options ...Option) (*Tokenizer, error) {
+	t := &Tokenizer{}
+	for _, opt := range options {
+		if err := opt(t); err != nil {
+			return nil, err
+		}
+	}
+	if t.stemmer == nil {
+		t.stemmer = stemmer.Func(stemmer.Stem)
+	}
+	return t, nil
+}
+
+// Token is a token found in text.
+type Token struct {
+	Text      string    // Surface form, as found in text
+	Norm      string    // Normalized (case folded & stemmed) form
+	Type      TokenType // WordToken, NumberToken or the type of a Rule
+	Start     int       // Byte offset of Text in text
+	End       int       // Byte offset of the end of Text in text
+	RuneStart int       // Rune offset of Text in text
+	RuneEnd   int       // Rune offset of the end of Text in text
+	Index     int       // Position in the list of tokens
+}
+
+// Tokenize returns list of (case folded) tokens found in text.
+func (t *Tokenizer) Tokenize(text string) []string {
+	var tokens []string
+	for _, tok := range t.TokenizeWithOffsets(text) {
+		tokens = append(tokens, tok.Norm)
+	}
+	return tokens
+}
+
+// TokenizeWithOffsets returns the tokens found in text with their offsets.
+func (t *Tokenizer) TokenizeWithOffsets(text string) []Token {
+	return t.tokenize(text, false)
+}
+
+// tokenize returns the tokens found in text. If gaps is set, dropped words
+// (stop words) take a position: the Index of the following tokens is
+// incremented, like position increments in Lucene.
+func (t *Tokenizer) tokenize(text string, gaps bool) []Token {
+	data := []byte(text)
+	fold := cases.Fold() // not safe for concurrent use
+	var tokens []Token
+	offset, runeOffset, index := 0, 0, 0
+	for _, span := range segment(data, t.rules) {
+		runeStart := runeOffset + utf8.RuneCount(data[offset:span.start])
+		tokens, index = t.appendTokens(tokens, fold, text[span.start:span.end], span.typ, span.start, runeStart, index, gaps)
+		offset, runeOffset = span.end, runeStart+utf8.RuneCountInString(text[span.start:span.end])
 	}
 	return tokens
 }
+
+// appendTokens appends to tokens the tokens of text, found at start (in
+// bytes) and runeStart (in runes). It returns tokens unchanged if the text
+// should be dropped. index is the Index of the first token, the Index after
+// the last one is returned (dropped words count if gaps is set).
+func (t *Tokenizer) appendTokens(tokens []Token, fold cases.Caser, text string, typ TokenType, start, runeStart, index int, gaps bool) ([]Token, int) {
+	add := func(text, norm string) {
+		runeEnd := runeStart + utf8.RuneCountInString(text)
+		tokens = append(tokens, Token{
+			Text:      text,
+			Norm:      norm,
+			Type:      typ,
+			Start:     start,
+			End:       start + len(text),
+			RuneStart: runeStart,
+			RuneEnd:   runeEnd,
+			Index:     index,
+		})
+		start, runeStart = start+len(text), runeEnd
+		index++
+	}
+
+	drop := func() {
+		if gaps {
+			index++
+		}
+	}
+
+	if typ != WordToken && typ != NumberToken { // rule match
+		add(text, t.normalize(fold, text))
+		return tokens, index
+	}
+
+	if t.contractions {
+		if size, first, second, ok := splitContraction(text); ok {
+			if first == "" {
+				first = text[:size]
+			}
+			if norm, ok := t.normToken(fold, first); ok {
+				add(text[:size], norm)
+			} else {
+				start, runeStart = start+size, runeStart+utf8.RuneCountInString(text[:size])
+				drop()
+			}
+			if norm, ok := t.normToken(fold, second); ok {
+				add(text[size:], norm)
+			} else {
+				drop()
+			}
+			return tokens, index
+		}
+	}
+
+	if norm, ok := t.normToken(fold, text); ok {
+		add(text, norm)
+	} else {
+		drop()
+	}
+	return tokens, index
+}
+
+// normToken returns the normalized (case folded & stemmed) form of word,
+// ok is false if the word should be dropped.
+func (t *Tokenizer) normToken(fold cases.Caser, word string) (token string, ok bool) {
+	token = t.normalize(fold, word)
+	if t.stopWords.Contains(token) {
+		return "", false
+	}
+	if t.stemmer != nil {
+		token = t.stemmer.Stem(token)
+	}
+	return token, len(token) != 0
+}
+
+// normalize returns the case folded version of word.
+func (t *Tokenizer) normalize(fold cases.Caser, word string) string {
+	if t.nfkc {
+		word = norm.NFKC.String(word)
+	}
+	return apostrophes.Replace(fold.String(word))
+}
+
+// Tokenize returns list of (case folded) tokens found in text, using the
+// English stemmer.
+func Tokenize(text string) []string {
+	return defaultTokenizer.Tokenize(text)
+}
+
+// TokenizeWithOffsets returns the tokens found in text with their offsets,
+// using the English stemmer.
+func TokenizeWithOffsets(text string) []Token {
+	return defaultTokenizer.TokenizeWithOffsets(text)
+}
@@ -2,8 +2,12 @@ package nlp
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/osshu320/nlp/stemmer"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/stretchr/testify/require"
 )
@@ -22,8 +26,55 @@ var tokenizeCases = []struct {
 // Use github.com/BurntSushi/toml to read TOML
 
 type tokenizeCase struct {
-	Text   string
-	Tokens []string
+	Text         string
+	Language     string
+	Lemmatize    bool
+	NFKC         bool
+	StopWords    bool     `toml:"stop_words"` // use built-in stop words for Language
+	Rules        []string // names of built-in rules
+	Contractions bool
+	Tokens       []string
+}
+
+var builtinRules = map[string]Rule{
+	"url":     URLRule,
+	"email":   EmailRule,
+	"hashtag": HashtagRule,
+	"mention": MentionRule,
+	"number":  NumberRule,
+}
+
+func (tc tokenizeCase) tokenizer(t *testing.T) *Tokenizer {
+	var options []Option
+	if tc.Language != "" {
+		options = append(options, WithLanguage(tc.Language))
+	}
+	if tc.Lemmatize {
+		options = append(options, WithLemmatizer())
+	}
+	if tc.NFKC {
+		options = append(options, WithNFKC())
+	}
+	for _, name := range tc.Rules {
+		rule, ok := builtinRules[name]
+		require.True(t, ok, "unknown rule %q", name)
+		options = append(options, WithRules(rule))
+	}
+	if tc.Contractions {
+		options = append(options, WithContractions())
+	}
+	if tc.StopWords {
+		lang := tc.Language
+		if lang == "" {
+			lang = "en"
+		}
+		sw, err := BuiltinStopWords(lang)
+		require.NoError(t, err)
+		options = append(options, WithStopWords(sw))
+	}
+	tok, err := NewTokenizer(options...)
+	require.NoError(t, err)
+	return tok
 }
 
 func loadTokenizeCases(t *testing.T) []tokenizeCase {
@@ -43,7 +94,7 @@ func TestTokenizeTable(t *testing.T) {
 	// for _, tc := range tokenizeCases {
 	for _, tc := range loadTokenizeCases(t) {
 		t.Run(tc.Text, func(t *testing.T) {
-			tokens := Tokenize(tc.Text)
+			tokens := tc.tokenizer(t).Tokenize(tc.Text)
 			require.Equal(t, tc.Tokens, tokens)
 		})
 	}
@@ -61,3 +112,99 @@ func TestTokenize(t *testing.T) {
 	*/
 	require.Equal(t, expected, tokens)
 }
+
+func TestTokenizerLanguage(t *testing.T) {
+	tok, err := NewTokenizer(WithLanguage("none"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"who's", "running"}, tok.Tokenize("Who's running?"))
+
+	tok, err = NewTokenizer(WithLanguage("es"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"chic", "torn"}, tok.Tokenize("chicos tornar"))
+
+	_, err = NewTokenizer(WithLanguage("xx"))
+	require.Error(t, err)
+}
+
+func TestTokenizerLemmatizer(t *testing.T) {
+	tok, err := NewTokenizer(WithLemmatizer(), WithLemmatizer())
+	require.NoError(t, err)
+	require.Equal(t, []string{"mouse"}, tok.Tokenize("mice"))
+
+	for _, options := range [][]Option{
+		{WithLemmatizer(), WithLanguage("en")},
+		{WithLanguage("en"), WithLemmatizer()},
+		{WithLemmatizer(), WithStemmer(stemmer.Func(stemmer.Stem))},
+		{WithStemmer(stemmer.Func(stemmer.Stem)), WithLemmatizer()},
+	} {
+		_, err := NewTokenizer(options...)
+		require.Error(t, err)
+	}
+}
+
+func TestLoadStopWords(t *testing.T) {
+	data := `
+# comment
+Über
+Who’s
+`
+	sw, err := LoadStopWords(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, StopWords{"über": true, "who's": true}, sw)
+
+	fileName := filepath.Join(t.TempDir(), "stop.txt")
+	err = os.WriteFile(fileName, []byte("sherlock\nholmes\n"), 0o644)
+	require.NoError(t, err)
+	extra, err := LoadStopWordsFile(fileName)
+	require.NoError(t, err)
+
+	en, err := BuiltinStopWords("en")
+	require.NoError(t, err)
+	tok, err := NewTokenizer(WithStopWords(en, extra))
+	require.NoError(t, err)
+	require.Equal(t, []string{"said", "watson"}, tok.Tokenize("Sherlock Holmes said to Watson"))
+
+	_, err = BuiltinStopWords("xx")
+	require.Error(t, err)
+}
+
+func TestTokenizeWithOffsets(t *testing.T) {
+	text := "Où est Holmes? Sherlock’s out."
+	expected := []Token{
+		{Text: "Où", Norm: "où", Type: WordToken, Start: 0, End: 3, RuneStart: 0, RuneEnd: 2, Index: 0},
+		{Text: "est", Norm: "est", Type: WordToken, Start: 4, End: 7, RuneStart: 3, RuneEnd: 6, Index: 1},
+		{Text: "Holmes", Norm: "holm", Type: WordToken, Start: 8, End: 14, RuneStart: 7, RuneEnd: 13, Index: 2},
+		{Text: "Sherlock’s", Norm: "sherlock", Type: WordToken, Start: 16, End: 28, RuneStart: 15, RuneEnd: 25, Index: 3},
+		{Text: "out", Norm: "out", Type: WordToken, Start: 29, End: 32, RuneStart: 26, RuneEnd: 29, Index: 4},
+	}
+	tokens := TokenizeWithOffsets(text)
+	require.Equal(t, expected, tokens)
+
+	runes := []rune(text)
+	for _, tok := range tokens {
+		require.Equal(t, tok.Text, text[tok.Start:tok.End])
+		require.Equal(t, tok.Text, string(runes[tok.RuneStart:tok.RuneEnd]))
+	}
+}
+
+func TestTokenizeRules(t *testing.T) {
+	issue, err := NewRule("issue", `[A-Z]+-\d+`)
+	require.NoError(t, err)
+	tok, err := NewTokenizer(WithRules(URLRule, issue), WithContractions())
+	require.NoError(t, err)
+
+	text := "Don’t fix NLP-42 at https://go.dev, 3.14"
+	expected := []Token{
+		{Text: "Do", Norm: "do", Type: WordToken, Start: 0, End: 2, RuneStart: 0, RuneEnd: 2, Index: 0},
+		{Text: "n’t", Norm: "not", Type: WordToken, Start: 2, End: 7, RuneStart: 2, RuneEnd: 5, Index: 1},
+		{Text: "fix", Norm: "fix", Type: WordToken, Start: 8, End: 11, RuneStart: 6, RuneEnd: 9, Index: 2},
+		{Text: "NLP-42", Norm: "nlp-42", Type: "issue", Start: 12, End: 18, RuneStart: 10, RuneEnd: 16, Index: 3},
+		{Text: "at", Norm: "at", Type: WordToken, Start: 19, End: 21, RuneStart: 17, RuneEnd: 19, Index: 4},
+		{Text: "https://go.dev", Norm: "https://go.dev", Type: URLToken, Start: 22, End: 36, RuneStart: 20, RuneEnd: 34, Index: 5},
+		{Text: "3.14", Norm: "3.14", Type: NumberToken, Start: 38, End: 42, RuneStart: 36, RuneEnd: 40, Index: 6},
+	}
+	require.Equal(t, expected, tok.TokenizeWithOffsets(text))
+
+	_, err = NewRule("bad", `(`)
+	require.Error(t, err)
+}
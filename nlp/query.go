@@ -0,0 +1,294 @@
+package nlp
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Query grammar:
+//
+//	query   = or
+//	or      = and { "OR" and }
+//	and     = not { ["AND"] not }
+//	not     = "NOT" not | primary
+//	primary = word | '"' phrase '"' | "(" or ")"
+
+// queryNode is a node in a parsed query.
+type queryNode interface {
+	// match returns the documents matching the node.
+	match(ix *Index) docSet
+	// terms returns the terms used for scoring (terms under NOT are ignored).
+	terms() []string
+}
+
+type termNode struct {
+	term string
+}
+
+func (n termNode) match(ix *Index) docSet { return ix.termDocs(n.term) }
+func (n termNode) terms() []string        { return []string{n.term} }
+
+type phraseNode struct {
+	words   []string
+	offsets []int // positions of words relative to the first one
+}
+
+func (n phraseNode) match(ix *Index) docSet { return ix.phraseDocs(n.words, n.offsets) }
+func (n phraseNode) terms() []string        { return n.words }
+
+type andNode struct {
+	nodes []queryNode
+}
+
+func (n andNode) match(ix *Index) docSet {
+	docs := n.nodes[0].match(ix)
+	for _, node := range n.nodes[1:] {
+		other := node.match(ix)
+		for id := range docs {
+			if !other[id] {
+				delete(docs, id)
+			}
+		}
+	}
+	return docs
+}
+
+func (n andNode) terms() []string {
+	var terms []string
+	for _, node := range n.nodes {
+		terms = append(terms, node.terms()...)
+	}
+	return terms
+}
+
+type orNode struct {
+	nodes []queryNode
+}
+
+func (n orNode) match(ix *Index) docSet {
+	docs := make(docSet)
+	for _, node := range n.nodes {
+		for id := range node.match(ix) {
+			docs[id] = true
+		}
+	}
+	return docs
+}
+
+func (n orNode) terms() []string {
+	return andNode(n).terms()
+}
+
+type notNode struct {
+	node queryNode
+}
+
+func (n notNode) match(ix *Index) docSet {
+	docs := ix.allDocs()
+	for id := range n.node.match(ix) {
+		delete(docs, id)
+	}
+	return docs
+}
+
+func (n notNode) terms() []string { return nil }
+
+// emptyQuery matches no documents, it's used when all the query words are
+// stop words.
+type emptyQuery struct{}
+
+func (emptyQuery) match(ix *Index) docSet { return nil }
+func (emptyQuery) terms() []string        { return nil }
+
+type itemType int
+
+const (
+	wordItem itemType = iota
+	phraseItem
+	openItem
+	closeItem
+)
+
+type queryItem struct {
+	typ  itemType
+	text string
+}
+
+// lexQuery splits a query to items.
+func lexQuery(query string) ([]queryItem, error) {
+	var items []queryItem
+	rs := []rune(query)
+	for i := 0; i < len(rs); {
+		switch r := rs[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			items = append(items, queryItem{openItem, "("})
+			i++
+		case r == ')':
+			items = append(items, queryItem{closeItem, ")"})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(rs) && rs[end] != '"' {
+				end++
+			}
+			if end == len(rs) {
+				return nil, fmt.Errorf("unterminated phrase at %d", i)
+			}
+			items = append(items, queryItem{phraseItem, string(rs[i+1 : end])})
+			i = end + 1
+		default:
+			end := i
+			for end < len(rs) && !unicode.IsSpace(rs[end]) && !strings.ContainsRune(`()"`, rs[end]) {
+				end++
+			}
+			items = append(items, queryItem{wordItem, string(rs[i:end])})
+			i = end
+		}
+	}
+	return items, nil
+}
+
+type queryParser struct {
+	items     []queryItem
+	tokenizer *Tokenizer
+}
+
+// parseQuery parses a query, words are normalized by tokenizer.
+func parseQuery(query string, tokenizer *Tokenizer) (queryNode, error) {
+	items, err := lexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := queryParser{items: items, tokenizer: tokenizer}
+	if len(items) == 0 {
+		return emptyQuery{}, nil
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if len(p.items) > 0 {
+		return nil, fmt.Errorf("unexpected %q in query", p.items[0].text)
+	}
+	if node == nil {
+		return emptyQuery{}, nil
+	}
+	return node, nil
+}
+
+// peekOp reports if the next item is the operator op.
+func (p *queryParser) peekOp(op string) bool {
+	return len(p.items) > 0 && p.items[0].typ == wordItem && p.items[0].text == op
+}
+
+func (p *queryParser) next() queryItem {
+	item := p.items[0]
+	p.items = p.items[1:]
+	return item
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	var nodes []queryNode
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+		if !p.peekOp("OR") {
+			break
+		}
+		p.next()
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nodes[0], nil
+	}
+	return orNode{nodes}, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	var nodes []queryNode
+	for {
+		if p.peekOp("AND") {
+			p.next()
+		}
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+		if len(p.items) == 0 || p.items[0].typ == closeItem || p.peekOp("OR") {
+			break
+		}
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nodes[0], nil
+	}
+	return andNode{nodes}, nil
+}
+
+func (p *queryParser) parseNot() (queryNode, error) {
+	if !p.peekOp("NOT") {
+		return p.parsePrimary()
+	}
+
+	p.next()
+	node, err := p.parseNot()
+	if err != nil || node == nil {
+		return nil, err
+	}
+	return notNode{node}, nil
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	if len(p.items) == 0 {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+
+	item := p.next()
+	switch item.typ {
+	case openItem:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if len(p.items) == 0 || p.items[0].typ != closeItem {
+			return nil, fmt.Errorf("missing ) in query")
+		}
+		p.next()
+		return node, nil
+	case closeItem:
+		return nil, fmt.Errorf("unexpected ) in query")
+	}
+
+	// word or phrase
+	tokens := p.tokenizer.tokenize(item.text, true)
+	switch len(tokens) {
+	case 0: // stop words
+		return nil, nil
+	case 1:
+		return termNode{tokens[0].Norm}, nil
+	}
+	var phrase phraseNode
+	for _, tok := range tokens {
+		phrase.words = append(phrase.words, tok.Norm)
+		phrase.offsets = append(phrase.offsets, tok.Index-tokens[0].Index)
+	}
+	return phrase, nil
+}
@@ -0,0 +1,69 @@
+package nlp
+
+import (
+	"regexp"
+)
+
+// TokenType is the type of a token.
+type TokenType string
+
+const (
+	WordToken    TokenType = "word"
+	NumberToken  TokenType = "number"
+	URLToken     TokenType = "url"
+	EmailToken   TokenType = "email"
+	HashtagToken TokenType = "hashtag"
+	MentionToken TokenType = "mention"
+)
+
+// Rule recognizes special tokens, such as URLs, that the word segmentation
+// would split. Tokens matched by a rule are case folded but not stemmed nor
+// checked for stop words.
+type Rule struct {
+	Type TokenType
+	re   *regexp.Regexp
+}
+
+// Built-in rules.
+var (
+	URLRule     = mustRule(URLToken, `(?:(?:https?|ftp)://|www\.)[^\s<>"]*[^\s<>"'.,;:!?)\]]`)
+	EmailRule   = mustRule(EmailToken, `[\pL\pN._%+-]+@[\pL\pN-]+(?:\.[\pL\pN-]+)+`)
+	HashtagRule = mustRule(HashtagToken, `#[\pL_][\pL\pN_]*`)
+	MentionRule = mustRule(MentionToken, `@[\pL\pN_]+`)
+	NumberRule  = mustRule(NumberToken, `[-+]?\pN+(?:[.,]\pN+)*(?:[eE][-+]?\pN+)?%?`)
+)
+
+// NewRule returns a rule emitting tokens of type typ for text matching the
+// regular expression pattern. Rules are tried where a token may start (not
+// after a letter or a digit), pattern is matched at that position only.
+// A match may not need more than 256 bytes of look ahead after its end.
+func NewRule(typ TokenType, pattern string) (Rule, error) {
+	re, err := regexp.Compile(`^(?:` + pattern + `)`)
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{Type: typ, re: re}, nil
+}
+
+func mustRule(typ TokenType, pattern string) Rule {
+	r, err := NewRule(typ, pattern)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// ruleLookAhead is the number of bytes needed after a token to know that no
+// rule would match a longer token (see NewRule).
+const ruleLookAhead = 256
+
+// matchRules returns the size and type of the longest match of rules at the
+// start of data, the first rule wins ties. size is 0 if no rule matches.
+func matchRules(data []byte, rules []Rule) (size int, typ TokenType) {
+	for _, r := range rules {
+		if loc := r.re.FindIndex(data); loc != nil && loc[1] > size {
+			size, typ = loc[1], r.Type
+		}
+	}
+	return size, typ
+}
@@ -0,0 +1,147 @@
+package nlp
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+)
+
+const (
+	// MaxWordSize is the maximal size of a word read by a Scanner.
+	MaxWordSize = 64 * 1024
+
+	// lookAhead is the number of bytes needed after a word to know it ended
+	// ("who" + "'s").
+	lookAhead = 2 * utf8.UTFMax
+)
+
+// Scanner reads tokens from an io.Reader, one at a time. It uses constant
+// memory regardless of the input size.
+//
+//	s := nlp.NewScanner(r)
+//	for s.Scan() {
+//		tok := s.Token()
+//		...
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+type Scanner struct {
+	tokenizer *Tokenizer
+	scanner   *bufio.Scanner
+	fold      cases.Caser
+
+	offset     int       // bytes consumed from the reader
+	runeOffset int       // runes consumed from the reader
+	prev       runeClass // class of the last rune consumed
+	start      int       // offset of current word
+	runeStart  int       // rune offset of current word
+	typ        TokenType // type of current word
+	index      int       // index of next token
+	buf        []Token
+	pending    []Token // tokens of current word not returned yet (contractions)
+	token      Token
+}
+
+// NewScanner returns a Scanner reading tokens from r with the tokenizer t.
+func (t *Tokenizer) NewScanner(r io.Reader) *Scanner {
+	s := Scanner{
+		tokenizer: t,
+		scanner:   bufio.NewScanner(r),
+		fold:      cases.Fold(),
+	}
+	s.scanner.Buffer(make([]byte, 4096), MaxWordSize)
+	s.scanner.Split(s.split)
+	return &s
+}
+
+// NewScanner returns a Scanner reading tokens from r, using the English
+// stemmer.
+func NewScanner(r io.Reader) *Scanner {
+	return defaultTokenizer.NewScanner(r)
+}
+
+// Scan advances the scanner to the next token, which will then be available
+// through the Token method. It returns false when there are no more tokens,
+// either by reaching the end of the input or an error.
+func (s *Scanner) Scan() bool {
+	for len(s.pending) == 0 {
+		if !s.scanner.Scan() {
+			return false
+		}
+		word := string(s.scanner.Bytes())
+		s.buf, s.index = s.tokenizer.appendTokens(s.buf[:0], s.fold, word, s.typ, s.start, s.runeStart, s.index, false)
+		s.pending = s.buf
+	}
+
+	s.token, s.pending = s.pending[0], s.pending[1:]
+	return true
+}
+
+// Token returns the most recent token generated by a call to Scan.
+func (s *Scanner) Token() Token {
+	return s.token
+}
+
+// Err returns the first non-EOF error that was encountered by the Scanner.
+func (s *Scanner) Err() error {
+	return s.scanner.Err()
+}
+
+// split is a bufio.SplitFunc returning words.
+func (s *Scanner) split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	// Don't look into a rune that was cut in the middle
+	safe := len(data)
+	if !atEOF {
+		safe -= partialRuneSize(data)
+	}
+
+	rules := s.tokenizer.rules
+	margin := lookAhead
+	if len(rules) > 0 {
+		margin = ruleLookAhead
+	}
+
+	start, end, typ, ok := nextToken(data[:safe], s.prev, rules)
+	switch {
+	case !ok && (atEOF || len(rules) == 0): // skip junk
+		advance = start
+	case !ok: // skip junk, but keep its end since a rule might match there
+		advance = safe - margin
+		for advance > 0 && !utf8.RuneStart(data[advance]) {
+			advance--
+		}
+		if advance < 0 {
+			advance = 0
+		}
+	case end+margin > safe && !atEOF: // token might continue, need more data
+		advance = start
+	default:
+		advance, token = end, data[start:end]
+		s.start = s.offset + start
+		s.runeStart = s.runeOffset + utf8.RuneCount(data[:start])
+		s.typ = typ
+	}
+
+	if advance > 0 {
+		s.prev = lastClass(data[:advance])
+	}
+	s.offset += advance
+	s.runeOffset += utf8.RuneCount(data[:advance])
+	return advance, token, nil
+}
+
+// partialRuneSize returns the size of the incomplete rune at the end of data.
+func partialRuneSize(data []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if utf8.FullRune(data[len(data)-i:]) {
+				return 0
+			}
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package nlp
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func scanAll(t *testing.T, s *Scanner) []Token {
+	var tokens []Token
+	for s.Scan() {
+		tokens = append(tokens, s.Token())
+	}
+	require.NoError(t, s.Err())
+	return tokens
+}
+
+func TestScanner(t *testing.T) {
+	for _, tc := range loadTokenizeCases(t) {
+		t.Run(tc.Text, func(t *testing.T) {
+			tok := tc.tokenizer(t)
+			expected := tok.TokenizeWithOffsets(tc.Text)
+
+			// One byte at a time cuts words and runes in the middle
+			r := iotest.OneByteReader(strings.NewReader(tc.Text))
+			tokens := scanAll(t, tok.NewScanner(r))
+			require.Equal(t, expected, tokens)
+		})
+	}
+}
+
+func TestScannerLarge(t *testing.T) {
+	text := strings.Repeat("Où est   Sherlock’s café? 3.14 ", 10_000)
+	expected := TokenizeWithOffsets(text)
+	tokens := scanAll(t, NewScanner(strings.NewReader(text)))
+	require.Equal(t, expected, tokens)
+}
+
+func TestScannerTooLong(t *testing.T) {
+	text := strings.Repeat("a", MaxWordSize+1)
+	s := NewScanner(strings.NewReader(text))
+	require.False(t, s.Scan())
+	require.Error(t, s.Err())
+}
+
+func TestScannerRules(t *testing.T) {
+	tok, err := NewTokenizer(WithRules(URLRule, EmailRule, HashtagRule), WithContractions())
+	require.NoError(t, err)
+
+	// long URLs cross buffer boundaries
+	url := "https://example.com/" + strings.Repeat("path/", 100)
+	text := strings.Repeat("See "+url+" #golang,  don't   ", 100)
+	expected := tok.TokenizeWithOffsets(text)
+	require.Len(t, expected, 500)
+	require.Equal(t, url, expected[1].Text)
+
+	tokens := scanAll(t, tok.NewScanner(strings.NewReader(text)))
+	require.Equal(t, expected, tokens)
+
+	// long junk before a rule match
+	text = strings.Repeat("-…", 5000) + "#golang"
+	tokens = scanAll(t, tok.NewScanner(strings.NewReader(text)))
+	require.Equal(t, tok.TokenizeWithOffsets(text), tokens)
+	require.Len(t, tokens, 1)
+}
@@ -0,0 +1,197 @@
+package nlp
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Word segmentation, a simplified version of Unicode word boundaries
+// (https://unicode.org/reports/tr29/#Word_Boundaries):
+//   - Words are sequences of letters, marks, digits and connectors ("_")
+//   - Apostrophes & periods inside letters ("who's", "e.g") don't break words
+//   - Periods & commas inside digits ("3.14", "1,000") don't break words
+//   - Each ideograph (Han) or Hiragana character is a word
+//   - Katakana sequences are words
+
+type runeClass int
+
+const (
+	otherClass runeClass = iota
+	letterClass
+	digitClass
+	connectorClass // "_"
+	ideoClass      // Han & Hiragana
+	katakanaClass
+	extendClass // combining marks, zero width joiners ...
+)
+
+func classOf(r rune) runeClass {
+	switch {
+	case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hiragana, r):
+		return ideoClass
+	case unicode.Is(unicode.Katakana, r), r == 'ー':
+		return katakanaClass
+	case unicode.IsLetter(r):
+		return letterClass
+	case unicode.IsDigit(r):
+		return digitClass
+	case unicode.Is(unicode.Pc, r):
+		return connectorClass
+	case unicode.IsMark(r), r == '‍', r == '‌':
+		return extendClass
+	}
+	return otherClass
+}
+
+// midLetter are runes that don't break words between letters.
+func isMidLetter(r rune) bool {
+	switch r {
+	case '\'', '’', '.', '·':
+		return true
+	}
+	return false
+}
+
+// midNum are runes that don't break words between digits.
+func isMidNum(r rune) bool {
+	switch r {
+	case '.', ',', '\'', '’':
+		return true
+	}
+	return false
+}
+
+// isWordStart reports if c may start a word.
+func isWordStart(c runeClass) bool {
+	return c != otherClass && c != extendClass
+}
+
+// joins reports if a rune of class next continues a word whose last
+// (non extend) rune is of class prev.
+func joins(prev, next runeClass) bool {
+	switch prev {
+	case ideoClass:
+		return false
+	case katakanaClass:
+		return next == katakanaClass || next == connectorClass
+	case letterClass, digitClass, connectorClass:
+		return next == letterClass || next == digitClass || next == connectorClass
+	}
+	return false
+}
+
+// nextWord returns the byte offsets of the first word in data.
+// ok is false if there are no words in data.
+func nextWord(data []byte) (start, end int, ok bool) {
+	// skip to word start
+	var cls runeClass
+	for start < len(data) {
+		r, size := utf8.DecodeRune(data[start:])
+		cls = classOf(r)
+		if isWordStart(cls) {
+			break
+		}
+		start += size
+	}
+	if start == len(data) {
+		return len(data), len(data), false
+	}
+
+	_, size := utf8.DecodeRune(data[start:])
+	end = start + size
+	for end < len(data) {
+		r, size := utf8.DecodeRune(data[end:])
+		next := classOf(r)
+		switch {
+		case next == extendClass:
+			end += size
+			continue
+		case joins(cls, next):
+			cls = next
+			end += size
+			continue
+		}
+
+		// "who's", "3.14" ...
+		if (cls == letterClass && isMidLetter(r)) || (cls == digitClass && isMidNum(r)) {
+			r2, size2 := utf8.DecodeRune(data[end+size:])
+			if classOf(r2) == cls {
+				end += size + size2
+				continue
+			}
+		}
+		break
+	}
+	return start, end, true
+}
+
+// wordType returns the type of a word found by nextWord.
+func wordType(word []byte) TokenType {
+	r, _ := utf8.DecodeRune(word)
+	if classOf(r) != digitClass {
+		return WordToken
+	}
+	for _, r := range string(word) {
+		if classOf(r) != digitClass && !isMidNum(r) {
+			return WordToken // "3d"
+		}
+	}
+	return NumberToken
+}
+
+// nextToken returns the byte offsets and type of the first token in data,
+// either a word or a match of one of rules. prev is the class of the rune
+// before data, rules are only tried where it's not part of a word.
+// ok is false if there are no tokens in data.
+func nextToken(data []byte, prev runeClass, rules []Rule) (start, end int, typ TokenType, ok bool) {
+	if len(rules) == 0 {
+		start, end, ok = nextWord(data)
+		return start, end, wordType(data[start:end]), ok
+	}
+
+	for start < len(data) {
+		r, size := utf8.DecodeRune(data[start:])
+		if prev == otherClass {
+			if n, typ := matchRules(data[start:], rules); n > 0 {
+				return start, start + n, typ, true
+			}
+		}
+		cls := classOf(r)
+		if isWordStart(cls) {
+			_, end, _ = nextWord(data[start:])
+			end += start
+			return start, end, wordType(data[start:end]), true
+		}
+		prev = cls
+		start += size
+	}
+	return len(data), len(data), "", false
+}
+
+// lastClass returns the class of the last rune in data.
+func lastClass(data []byte) runeClass {
+	if len(data) == 0 {
+		return otherClass
+	}
+	r, _ := utf8.DecodeLastRune(data)
+	return classOf(r)
+}
+
+type span struct {
+	start, end int
+	typ        TokenType
+}
+
+// segment returns the spans of the tokens (words or rule matches) in text.
+func segment(text []byte, rules []Rule) []span {
+	var spans []span
+	offset := 0
+	for {
+		start, end, typ, ok := nextToken(text[offset:], lastClass(text[:offset]), rules)
+		if !ok {
+			return spans
+		}
+		spans = append(spans, span{offset + start, offset + end, typ})
+		offset += end
+	}
+}
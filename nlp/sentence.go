@@ -0,0 +1,168 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// abbrevKind is when a word followed by a period is an abbreviation.
+type abbrevKind int
+
+const (
+	abbrevAlways abbrevKind = iota + 1
+	abbrevNumber            // before a number ("No. 5", "Dec. 25")
+	abbrevTitle             // capitalized ("St. Simon", "Col. Ross")
+)
+
+var (
+	// abbreviations are words that are followed by a period but don't end a
+	// sentence (in lower case, without the final period). Abbreviations that
+	// are also common words ("no", "dec") depend on the context.
+	abbreviations = map[string]abbrevKind{
+		"mr": abbrevAlways, "mrs": abbrevAlways, "ms": abbrevAlways, "messrs": abbrevAlways,
+		"dr": abbrevAlways, "prof": abbrevAlways, "rev": abbrevAlways, "hon": abbrevAlways,
+		"sr": abbrevAlways, "jr": abbrevAlways, "e.g": abbrevAlways, "i.e": abbrevAlways,
+		"cf": abbrevAlways, "vs": abbrevAlways, "viz": abbrevAlways, "a.m": abbrevAlways,
+		"p.m": abbrevAlways, "approx": abbrevAlways, "inc": abbrevAlways, "ltd": abbrevAlways,
+		"corp": abbrevAlways, "u.s": abbrevAlways, "u.k": abbrevAlways,
+
+		"no": abbrevNumber, "nos": abbrevNumber, "fig": abbrevNumber, "vol": abbrevNumber,
+		"ch": abbrevNumber, "pp": abbrevNumber, "jan": abbrevNumber, "feb": abbrevNumber,
+		"mar": abbrevNumber, "apr": abbrevNumber, "jun": abbrevNumber, "jul": abbrevNumber,
+		"aug": abbrevNumber, "sep": abbrevNumber, "sept": abbrevNumber, "oct": abbrevNumber,
+		"nov": abbrevNumber, "dec": abbrevNumber,
+
+		"st": abbrevTitle, "mt": abbrevTitle, "capt": abbrevTitle, "col": abbrevTitle,
+		"gen": abbrevTitle, "lt": abbrevTitle, "sgt": abbrevTitle, "gov": abbrevTitle,
+		"sen": abbrevTitle, "rep": abbrevTitle, "co": abbrevTitle,
+	}
+)
+
+// Sentence is a sentence found in text.
+type Sentence struct {
+	Text  string
+	Start int // Byte offset of Text in text
+	End   int // Byte offset of the end of Text in text
+}
+
+// Sentences splits text to sentences. Sentences end with ".", "!", "?" or
+// "…" (and following closing quotes or brackets) followed by a space and a
+// word that is not in lower case, or at a paragraph break (an empty line).
+// Abbreviations ("Mr.", "e.g.", "No. 5"), initials ("A. Conan Doyle") and
+// numbers ("3.14") don't end sentences.
+func Sentences(text string) []Sentence {
+	var sentences []Sentence
+	add := func(start, end int) {
+		s := text[start:end]
+		trimmed := strings.TrimLeftFunc(s, unicode.IsSpace)
+		start += len(s) - len(trimmed)
+		trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+		if trimmed != "" {
+			sentences = append(sentences, Sentence{trimmed, start, start + len(trimmed)})
+		}
+	}
+
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '\n' && isParagraphBreak(text[i+size:]):
+			add(start, i)
+			start = i
+			i += size
+		case isTerminator(r):
+			end := i + size
+			for end < len(text) {
+				r, size := utf8.DecodeRuneInString(text[end:])
+				if !isTerminator(r) && !isClosing(r) {
+					break
+				}
+				end += size
+			}
+			if isSentenceEnd(text, i, end) {
+				add(start, end)
+				start = end
+			}
+			i = end
+		default:
+			i += size
+		}
+	}
+	add(start, len(text))
+	return sentences
+}
+
+func isTerminator(r rune) bool {
+	return strings.ContainsRune(".!?…。！？", r)
+}
+
+// isClosing reports if r is a closing quote or bracket.
+func isClosing(r rune) bool {
+	return strings.ContainsRune(`"')]}’”»`, r)
+}
+
+// isParagraphBreak reports if text (after a new line) starts with an empty
+// line.
+func isParagraphBreak(text string) bool {
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			return true
+		case !unicode.IsSpace(r):
+			return false
+		}
+	}
+	return false
+}
+
+// isSentenceEnd reports if the terminator at text[i:end] ends a sentence.
+func isSentenceEnd(text string, i, end int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	if strings.ContainsRune("。！？", r) { // CJK, no spaces between sentences
+		return true
+	}
+
+	// must be followed by a space and a word not in lower case
+	rest := text[end:]
+	if rest == "" {
+		return true
+	}
+	if r, _ := utf8.DecodeRuneInString(rest); !unicode.IsSpace(r) {
+		return false
+	}
+	rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	if next, _ := utf8.DecodeRuneInString(rest); unicode.IsLower(next) {
+		return false
+	}
+
+	if r != '.' || (end > i+1 && text[i+1] == '.') { // "!", "?", "..."
+		return true
+	}
+
+	// word before the period
+	wstart := i
+	for wstart > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:wstart])
+		if !unicode.IsLetter(r) && r != '.' {
+			break
+		}
+		wstart -= size
+	}
+	word := text[wstart:i]
+	if n := utf8.RuneCountInString(word); n == 1 && word != "I" && unicode.IsUpper([]rune(word)[0]) {
+		return false // initial, not the pronoun "I"
+	}
+
+	switch abbreviations[strings.ToLower(word)] {
+	case abbrevAlways:
+		return false
+	case abbrevNumber:
+		next, _ := utf8.DecodeRuneInString(rest)
+		return !unicode.IsDigit(next)
+	case abbrevTitle:
+		first, _ := utf8.DecodeRuneInString(word)
+		return !unicode.IsUpper(first)
+	}
+	return true
+}
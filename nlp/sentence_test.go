@@ -0,0 +1,40 @@
+package nlp
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type sentenceCase struct {
+	Text      string
+	Sentences []string
+}
+
+func loadSentenceCases(t *testing.T) []sentenceCase {
+	data, err := os.ReadFile("sentence_cases.toml")
+	require.NoError(t, err)
+
+	var testCases struct {
+		Cases []sentenceCase
+	}
+
+	err = toml.Unmarshal(data, &testCases)
+	require.NoError(t, err, "Unmarshal TOML")
+	return testCases.Cases
+}
+
+func TestSentencesTable(t *testing.T) {
+	for _, tc := range loadSentenceCases(t) {
+		t.Run(tc.Text, func(t *testing.T) {
+			texts := []string{}
+			for _, s := range Sentences(tc.Text) {
+				require.Equal(t, s.Text, tc.Text[s.Start:s.End])
+				texts = append(texts, s.Text)
+			}
+			require.Equal(t, tc.Sentences, texts)
+		})
+	}
+}
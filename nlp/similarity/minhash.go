@@ -0,0 +1,149 @@
+package similarity
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// MinHasher computes MinHash signatures of token sets. The fraction of equal
+// values in the signatures of two sets estimates their Jaccard similarity.
+type MinHasher struct {
+	seeds []uint64
+}
+
+// NewMinHasher returns a MinHasher that computes signatures of size n. The
+// hash functions are derived from seed, signatures are comparable only if
+// they were computed with the same size and seed.
+func NewMinHasher(n int, seed uint64) *MinHasher {
+	seeds := make([]uint64, n)
+	for i := range seeds {
+		seed += 0x9e3779b97f4a7c15 // splitmix64
+		seeds[i] = mix64(seed)
+	}
+	return &MinHasher{seeds: seeds}
+}
+
+// mix64 is the splitmix64 finalizer.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Signature is a MinHash signature.
+type Signature []uint64
+
+// Signature returns the signature of the set of tokens. The signature of an
+// empty set has all values set to math.MaxUint64.
+func (h *MinHasher) Signature(tokens []string) Signature {
+	sig := make(Signature, len(h.seeds))
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+
+	for _, tok := range tokens {
+		f := fnv.New64a()
+		f.Write([]byte(tok))
+		th := f.Sum64()
+		for i, seed := range h.seeds {
+			if v := mix64(th ^ seed); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// Similarity returns the estimated Jaccard similarity of the sets with
+// signatures s and other. It panics if the signatures have different sizes.
+func (s Signature) Similarity(other Signature) float64 {
+	if len(s) != len(other) {
+		panic(fmt.Sprintf("similarity: signature sizes differ (%d != %d)", len(s), len(other)))
+	}
+	if len(s) == 0 {
+		return 0
+	}
+
+	equal := 0
+	for i, v := range s {
+		if v == other[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(s))
+}
+
+// LSH is a locality sensitive hashing index of MinHash signatures for near
+// duplicate detection. Signatures are split into bands of rows, documents
+// whose signatures are equal in at least one band are candidates. Two
+// documents with Jaccard similarity s are candidates with probability
+// 1 - (1 - s^rows)^bands, the threshold is about (1/bands)^(1/rows).
+// LSH is not safe for concurrent use.
+type LSH struct {
+	bands, rows int
+	buckets     []map[uint64][]string // band -> band hash -> IDs
+}
+
+// NewLSH returns an LSH index for signatures of size bands*rows.
+func NewLSH(bands, rows int) *LSH {
+	buckets := make([]map[uint64][]string, bands)
+	for i := range buckets {
+		buckets[i] = make(map[uint64][]string)
+	}
+	return &LSH{bands: bands, rows: rows, buckets: buckets}
+}
+
+// Add adds document id with signature sig to the index.
+func (l *LSH) Add(id string, sig Signature) error {
+	if err := l.check(sig); err != nil {
+		return err
+	}
+
+	for b := range l.buckets {
+		h := l.bandHash(sig, b)
+		l.buckets[b][h] = append(l.buckets[b][h], id)
+	}
+	return nil
+}
+
+// Candidates returns the IDs (sorted) of documents that share at least one
+// band with sig.
+func (l *LSH) Candidates(sig Signature) ([]string, error) {
+	if err := l.check(sig); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	for b := range l.buckets {
+		for _, id := range l.buckets[b][l.bandHash(sig, b)] {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (l *LSH) check(sig Signature) error {
+	if len(sig) != l.bands*l.rows {
+		return fmt.Errorf("signature size is %d, expected %d (%d bands * %d rows)", len(sig), l.bands*l.rows, l.bands, l.rows)
+	}
+	return nil
+}
+
+// bandHash returns the hash of band b of sig.
+func (l *LSH) bandHash(sig Signature, b int) uint64 {
+	var h uint64
+	for _, v := range sig[b*l.rows : (b+1)*l.rows] {
+		h = mix64(h ^ v)
+	}
+	return h
+}
@@ -0,0 +1,84 @@
+package similarity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osshu320/nlp"
+)
+
+func TestMinHashSimilarity(t *testing.T) {
+	h := NewMinHasher(256, 7)
+	a := nlp.Shingles("To Sherlock Holmes she is always the woman. I have seldom heard him mention her under any other name.", 4)
+	b := nlp.Shingles("To Sherlock Holmes she was always the woman. I have seldom heard him mention her by any other name.", 4)
+	c := nlp.Shingles("It was in the spring of the year 1894 that all London was interested.", 4)
+
+	sa, sb, sc := h.Signature(a), h.Signature(b), h.Signature(c)
+	require.Equal(t, 1.0, sa.Similarity(sa))
+	require.InDelta(t, Jaccard(a, b), sa.Similarity(sb), 0.1)
+	require.InDelta(t, Jaccard(a, c), sa.Similarity(sc), 0.1)
+
+	// same tokens in a different order and with duplicates
+	require.Equal(t, sa, h.Signature(append(append([]string{}, a[10:]...), a...)))
+	// different seed, different hash functions
+	require.NotEqual(t, sa, NewMinHasher(256, 8).Signature(a))
+
+	require.Panics(t, func() { sa.Similarity(sa[:10]) })
+}
+
+func TestLSH(t *testing.T) {
+	docs := map[string]string{
+		"scandal":  "To Sherlock Holmes she is always the woman. I have seldom heard him mention her under any other name.",
+		"scandal2": "To Sherlock Holmes she was always the woman. I have seldom heard him mention her under any other name.",
+		"league":   "I had called upon my friend, Mr. Sherlock Holmes, one day in the autumn of last year.",
+		"identity": "My dear fellow, said Sherlock Holmes as we sat on either side of the fire in his lodgings.",
+	}
+
+	h := NewMinHasher(64, 1)
+	l := NewLSH(16, 4)
+	sigs := make(map[string]Signature)
+	for id, text := range docs {
+		sigs[id] = h.Signature(nlp.Shingles(text, 4))
+		require.NoError(t, l.Add(id, sigs[id]))
+	}
+
+	ids, err := l.Candidates(sigs["scandal"])
+	require.NoError(t, err)
+	require.Equal(t, []string{"scandal", "scandal2"}, ids)
+
+	ids, err = l.Candidates(sigs["league"])
+	require.NoError(t, err)
+	require.Equal(t, []string{"league"}, ids)
+
+	_, err = l.Candidates(sigs["league"][:10])
+	require.Error(t, err)
+	require.Error(t, l.Add("short", Signature{1, 2}))
+}
+
+func ExampleLSH() {
+	docs := []string{
+		"The Adventure of the Speckled Band",
+		"The Adventure of the Speckled Band!",
+		"A Study in Scarlet",
+	}
+
+	h := NewMinHasher(32, 1)
+	l := NewLSH(8, 4)
+	for i, doc := range docs {
+		sig := h.Signature(nlp.Shingles(doc, 3))
+		ids, err := l.Candidates(sig)
+		if err != nil {
+			fmt.Println("error:", err)
+			return
+		}
+		fmt.Println(i, ids)
+		l.Add(fmt.Sprint(i), sig)
+	}
+
+	// Output:
+	// 0 []
+	// 1 [0]
+	// 2 []
+}
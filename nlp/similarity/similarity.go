@@ -0,0 +1,110 @@
+// Package similarity compares documents by their tokens, as returned by
+// nlp.Tokenize (or shingles, as returned by nlp.Shingles).
+package similarity
+
+import (
+	"math"
+)
+
+// Jaccard returns the Jaccard similarity of the sets of tokens a and b: the
+// size of their intersection divided by the size of their union. Two empty
+// sets have a similarity of 1.
+func Jaccard(a, b []string) float64 {
+	setA := toSet(a)
+	setB := toSet(b)
+	if len(setA) == 0 && len(setB) == 0 {
+		return 1
+	}
+
+	common := 0
+	for tok := range setA {
+		if setB[tok] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(setA)+len(setB)-common)
+}
+
+func toSet(tokens []string) map[string]bool {
+	set := make(map[string]bool, len(tokens))
+	for _, tok := range tokens {
+		set[tok] = true
+	}
+	return set
+}
+
+// Vector is a sparse vector of term weights.
+type Vector map[string]float64
+
+// Norm returns the Euclidean norm of v.
+func (v Vector) Norm() float64 {
+	sum := 0.0
+	for _, w := range v {
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
+
+// Cosine returns the cosine similarity of a and b, 0 if one of them is a
+// zero vector.
+func Cosine(a, b Vector) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	dot := 0.0
+	for term, w := range a {
+		dot += w * b[term]
+	}
+	if dot == 0 {
+		return 0
+	}
+	return dot / (a.Norm() * b.Norm())
+}
+
+// TFIDF computes TF-IDF vectors of documents, document frequencies are
+// learned from a corpus with Add.
+// TFIDF is not safe for concurrent use while documents are added.
+type TFIDF struct {
+	numDocs int
+	df      map[string]int // term -> number of documents containing it
+}
+
+// NewTFIDF returns a TFIDF with an empty corpus.
+func NewTFIDF() *TFIDF {
+	return &TFIDF{df: make(map[string]int)}
+}
+
+// Add adds a document, given by its tokens, to the corpus.
+func (m *TFIDF) Add(tokens []string) {
+	for tok := range toSet(tokens) {
+		m.df[tok]++
+	}
+	m.numDocs++
+}
+
+// Len returns the number of documents in the corpus.
+func (m *TFIDF) Len() int {
+	return m.numDocs
+}
+
+// Vector returns the TF-IDF vector of a document given by its tokens. The
+// weight of a term is (1 + log(tf)) * log(1 + N/df), where N is the number of
+// documents in the corpus. Terms that are not in the corpus are weighted as
+// if they appear in one document.
+func (m *TFIDF) Vector(tokens []string) Vector {
+	tf := make(map[string]int)
+	for _, tok := range tokens {
+		tf[tok]++
+	}
+
+	v := make(Vector, len(tf))
+	n := float64(m.numDocs)
+	for term, count := range tf {
+		df := m.df[term]
+		if df == 0 {
+			df = 1
+		}
+		v[term] = (1 + math.Log(float64(count))) * math.Log(1+n/float64(df))
+	}
+	return v
+}
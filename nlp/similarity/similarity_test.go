@@ -0,0 +1,154 @@
+package similarity
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osshu320/nlp"
+)
+
+var jaccardCases = []struct {
+	a, b     string
+	expected float64
+}{
+	{"", "", 1},
+	{"holmes", "", 0},
+	{"Sherlock Holmes", "Holmes, Sherlock", 1},
+	{"the red headed league", "the blue league", 2.0 / 5},
+	{"holmes holmes watson", "holmes", 1.0 / 2},
+}
+
+func TestJaccard(t *testing.T) {
+	for _, tc := range jaccardCases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			a, b := nlp.Tokenize(tc.a), nlp.Tokenize(tc.b)
+			require.InDelta(t, tc.expected, Jaccard(a, b), 1e-9)
+			require.InDelta(t, tc.expected, Jaccard(b, a), 1e-9)
+		})
+	}
+}
+
+func TestJaccardShingles(t *testing.T) {
+	a := nlp.Shingles("Sherlock Holmes", 3)
+	b := nlp.Shingles("sherlock  HOLMES", 3)
+	require.Equal(t, 1.0, Jaccard(a, b))
+
+	c := nlp.Shingles("Sherlock Homes", 3)
+	require.Greater(t, Jaccard(a, c), 0.5)
+	require.Less(t, Jaccard(a, c), 1.0)
+}
+
+func TestCosine(t *testing.T) {
+	require.InDelta(t, 1.0, Cosine(Vector{"a": 1, "b": 2}, Vector{"a": 2, "b": 4}), 1e-9)
+	require.Equal(t, 0.0, Cosine(Vector{"a": 1}, Vector{"b": 1}))
+	require.Equal(t, 0.0, Cosine(Vector{}, Vector{"b": 1}))
+	require.InDelta(t, 1/2.0, Cosine(Vector{"a": 1, "b": 1}, Vector{"a": 1, "c": 1}), 1e-9)
+}
+
+func TestTFIDF(t *testing.T) {
+	docs := []string{
+		"Sherlock Holmes and the violin",
+		"Sherlock Holmes and Doctor Watson",
+		"Moriarty and his gang",
+	}
+	m := NewTFIDF()
+	for _, doc := range docs {
+		m.Add(nlp.Tokenize(doc))
+	}
+	require.Equal(t, len(docs), m.Len())
+
+	v := m.Vector(nlp.Tokenize("Holmes played the violin"))
+	// rare terms weigh more
+	require.Greater(t, v["violin"], v["holm"])
+	// frequent terms weigh more
+	require.Greater(t, m.Vector([]string{"holm", "holm"})["holm"], v["holm"])
+
+	v0 := m.Vector(nlp.Tokenize(docs[0]))
+	v1 := m.Vector(nlp.Tokenize(docs[1]))
+	v2 := m.Vector(nlp.Tokenize(docs[2]))
+	require.InDelta(t, 1.0, Cosine(v0, v0), 1e-9)
+	require.Greater(t, Cosine(v0, v1), Cosine(v0, v2))
+}
+
+// loadCorpus returns the paragraphs of sherlock.txt, it skips the benchmark
+// if the file is missing.
+func loadCorpus(b *testing.B) []string {
+	data, err := os.ReadFile("../../freq/sherlock.txt")
+	if err != nil {
+		b.Skipf("can't load corpus: %s", err)
+	}
+
+	var paragraphs []string
+	for _, p := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+func tokenizeCorpus(b *testing.B) [][]string {
+	var docs [][]string
+	for _, p := range loadCorpus(b) {
+		docs = append(docs, nlp.Tokenize(p))
+	}
+	return docs
+}
+
+func BenchmarkJaccard(b *testing.B) {
+	docs := tokenizeCorpus(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Jaccard(docs[i%len(docs)], docs[(i+1)%len(docs)])
+	}
+}
+
+func BenchmarkTFIDFCosine(b *testing.B) {
+	docs := tokenizeCorpus(b)
+	m := NewTFIDF()
+	for _, doc := range docs {
+		m.Add(doc)
+	}
+	vectors := make([]Vector, len(docs))
+	for i, doc := range docs {
+		vectors[i] = m.Vector(doc)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Cosine(vectors[i%len(docs)], vectors[(i+1)%len(docs)])
+	}
+}
+
+func BenchmarkMinHashSignature(b *testing.B) {
+	docs := tokenizeCorpus(b)
+	h := NewMinHasher(128, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Signature(docs[i%len(docs)])
+	}
+}
+
+func BenchmarkLSH(b *testing.B) {
+	docs := tokenizeCorpus(b)
+	h := NewMinHasher(128, 1)
+	sigs := make([]Signature, len(docs))
+	for i, doc := range docs {
+		sigs[i] = h.Signature(doc)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := NewLSH(32, 4)
+		for j, sig := range sigs {
+			if _, err := l.Candidates(sig); err != nil {
+				b.Fatal(err)
+			}
+			if err := l.Add(strconv.Itoa(j), sig); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package stemmer
+
+// Snowball French stemmer.
+// See https://snowballstem.org/algorithms/french/stemmer.html
+
+const frVowels = "aeiouyâàëéêèïîôûù"
+
+var (
+	frStep1 = struct {
+		delete, ation, logie, usion, ence, ement, ite, ive, eaux, aux, euse, issement, amment, emment, ment []string
+	}{
+		delete: []string{
+			"ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes",
+			"ismes", "ables", "istes",
+		},
+		ation:    []string{"atrice", "ateur", "ation", "atrices", "ateurs", "ations"},
+		logie:    []string{"logie", "logies"},
+		usion:    []string{"usion", "ution", "usions", "utions"},
+		ence:     []string{"ence", "ences"},
+		ement:    []string{"ement", "ements"},
+		ite:      []string{"ité", "ités"},
+		ive:      []string{"if", "ive", "ifs", "ives"},
+		eaux:     []string{"eaux"},
+		aux:      []string{"aux"},
+		euse:     []string{"euse", "euses"},
+		issement: []string{"issement", "issements"},
+		amment:   []string{"amment"},
+		emment:   []string{"emment"},
+		ment:     []string{"ment", "ments"},
+	}
+
+	frIVerbs = []string{
+		"îmes", "ît", "îtes", "i", "ie", "ies", "ir", "ira", "irai",
+		"iraIent", "irais", "irait", "iras", "irent", "irez", "iriez",
+		"irions", "irons", "iront", "is", "issaIent", "issais", "issait",
+		"issant", "issante", "issantes", "issants", "isse", "issent",
+		"isses", "issez", "issiez", "issions", "issons", "it",
+	}
+
+	frVerbsIons = []string{"ions"}
+
+	frVerbsE = []string{
+		"é", "ée", "ées", "és", "èrent", "er", "era", "erai", "eraIent",
+		"erais", "erait", "eras", "erez", "eriez", "erions", "erons",
+		"eront", "ez", "iez",
+	}
+
+	frVerbsA = []string{
+		"âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant",
+		"ante", "antes", "ants", "as", "asse", "assent", "asses", "assiez",
+		"assions",
+	}
+
+	frResidual = []string{"ion", "ier", "ière", "Ier", "Ière", "e", "ë"}
+)
+
+func french(word string) string {
+	w := newSnowballWord(word, frVowels)
+	w.frPrelude()
+	w.markFrenchRV()
+	w.markR1R2()
+
+	changed := w.frStandardSuffix()
+	if !changed {
+		changed = w.frIVerbSuffix() || w.frVerbSuffix()
+	}
+	if changed {
+		switch {
+		case w.hasSuffix("Y"):
+			w.replace("Y", "i")
+		case w.hasSuffix("ç"):
+			w.replace("ç", "c")
+		}
+	} else {
+		w.frResidualSuffix()
+	}
+	w.frUndouble()
+	w.frUnaccent()
+
+	w.replaceRunes("IUY", "iuy")
+	return w.String()
+}
+
+// frPrelude marks vowels that act as consonants with upper case: u or i
+// between vowels, y before or after a vowel, and u after q.
+func (w *snowballWord) frPrelude() {
+	for i, r := range w.rs {
+		switch {
+		case w.isVowel(i-1) && w.isVowel(i+1) && (r == 'u' || r == 'i'):
+			w.rs[i] = r - 'a' + 'A'
+		case r == 'y' && (w.isVowel(i-1) || w.isVowel(i+1)):
+			w.rs[i] = 'Y'
+		case r == 'u' && i > 0 && w.rs[i-1] == 'q':
+			w.rs[i] = 'U'
+		}
+	}
+}
+
+// markFrenchRV sets RV: if the word begins with two vowels, RV is the region
+// after the third letter, otherwise the region after the first vowel not at
+// the beginning of the word. "par", "col" & "tap" are exceptions.
+func (w *snowballWord) markFrenchRV() {
+	switch {
+	case len(w.rs) >= 2 && w.isVowel(0) && w.isVowel(1):
+		w.rv = 3
+	case w.hasPrefix("par"), w.hasPrefix("col"), w.hasPrefix("tap"):
+		w.rv = 3
+	default:
+		w.rv = w.pastVowel(1)
+	}
+	if w.rv > len(w.rs) {
+		w.rv = len(w.rs)
+	}
+}
+
+func (w *snowballWord) hasPrefix(prefix string) bool {
+	rs := []rune(prefix)
+	return len(w.rs) >= len(rs) && string(w.rs[:len(rs)]) == prefix
+}
+
+// frStandardSuffix is step 1, it reports if a suffix was removed and verb
+// suffixes should not be looked for.
+func (w *snowballWord) frStandardSuffix() bool {
+	s := frStep1
+	suffix := w.longest(concat(
+		s.delete, s.ation, s.logie, s.usion, s.ence, s.ement, s.ite, s.ive,
+		s.eaux, s.aux, s.euse, s.issement, s.amment, s.emment, s.ment,
+	))
+	if suffix == "" {
+		return false
+	}
+
+	inR2 := w.in(suffix, w.r2)
+	switch {
+	case contains(s.delete, suffix):
+		if !inR2 {
+			return false
+		}
+		w.remove(suffix)
+	case contains(s.ation, suffix):
+		if !inR2 {
+			return false
+		}
+		w.remove(suffix)
+		w.frRemoveIc()
+	case contains(s.logie, suffix):
+		if !inR2 {
+			return false
+		}
+		w.replace(suffix, "log")
+	case contains(s.usion, suffix):
+		if !inR2 {
+			return false
+		}
+		w.replace(suffix, "u")
+	case contains(s.ence, suffix):
+		if !inR2 {
+			return false
+		}
+		w.replace(suffix, "ent")
+	case contains(s.ement, suffix):
+		if !w.in(suffix, w.rv) {
+			return false
+		}
+		w.remove(suffix)
+		switch pre := w.longest([]string{"iv", "eus", "abl", "ic", "ièr", "Ièr"}); pre {
+		case "iv":
+			if w.in(pre, w.r2) {
+				w.remove(pre)
+				w.removeInR2("at")
+			}
+		case "eus":
+			if w.in(pre, w.r2) {
+				w.remove(pre)
+			} else if w.in(pre, w.r1) {
+				w.replace(pre, "eux")
+			}
+		case "abl", "ic":
+			w.removeInR2(pre)
+		case "ièr", "Ièr":
+			if w.in(pre, w.rv) {
+				w.replace(pre, "i")
+			}
+		}
+	case contains(s.ite, suffix):
+		if !inR2 {
+			return false
+		}
+		w.remove(suffix)
+		switch pre := w.longest([]string{"abil", "ic", "iv"}); pre {
+		case "abil":
+			if w.in(pre, w.r2) {
+				w.remove(pre)
+			} else {
+				w.replace(pre, "abl")
+			}
+		case "ic":
+			w.frRemoveIc()
+		case "iv":
+			w.removeInR2(pre)
+		}
+	case contains(s.ive, suffix):
+		if !inR2 {
+			return false
+		}
+		w.remove(suffix)
+		if w.hasSuffix("at") && w.in("at", w.r2) {
+			w.remove("at")
+			w.frRemoveIc()
+		}
+	case contains(s.eaux, suffix):
+		w.replace(suffix, "eau")
+	case contains(s.aux, suffix):
+		if !w.in(suffix, w.r1) {
+			return false
+		}
+		w.replace(suffix, "al")
+	case contains(s.euse, suffix):
+		switch {
+		case inR2:
+			w.remove(suffix)
+		case w.in(suffix, w.r1):
+			w.replace(suffix, "eux")
+		default:
+			return false
+		}
+	case contains(s.issement, suffix):
+		if !w.in(suffix, w.r1) || w.isVowel(w.start(suffix)-1) {
+			return false
+		}
+		w.remove(suffix)
+	case contains(s.amment, suffix):
+		if w.in(suffix, w.rv) {
+			w.replace(suffix, "ant")
+		}
+		return false // continue to step 2a
+	case contains(s.emment, suffix):
+		if w.in(suffix, w.rv) {
+			w.replace(suffix, "ent")
+		}
+		return false
+	case contains(s.ment, suffix):
+		if i := w.start(suffix) - 1; i >= w.rv && w.isVowel(i) {
+			w.remove(suffix)
+		}
+		return false
+	}
+	return true
+}
+
+// frRemoveIc removes a final "ic" if in R2, otherwise replaces it by "iqU".
+func (w *snowballWord) frRemoveIc() {
+	if !w.hasSuffix("ic") {
+		return
+	}
+	if w.in("ic", w.r2) {
+		w.remove("ic")
+	} else {
+		w.replace("ic", "iqU")
+	}
+}
+
+// frIVerbSuffix is step 2a, it reports if a suffix was removed.
+func (w *snowballWord) frIVerbSuffix() bool {
+	suffix := w.longestIn(frIVerbs, w.rv)
+	if suffix == "" {
+		return false
+	}
+	if i := w.start(suffix) - 1; i < w.rv || w.isVowel(i) {
+		return false
+	}
+	w.remove(suffix)
+	return true
+}
+
+// frVerbSuffix is step 2b, it reports if a suffix was removed.
+func (w *snowballWord) frVerbSuffix() bool {
+	suffix := w.longestIn(concat(frVerbsIons, frVerbsE, frVerbsA), w.rv)
+	switch {
+	case suffix == "":
+		return false
+	case contains(frVerbsIons, suffix):
+		if !w.in(suffix, w.r2) {
+			return false
+		}
+		w.remove(suffix)
+	case contains(frVerbsE, suffix):
+		w.remove(suffix)
+	case contains(frVerbsA, suffix):
+		w.remove(suffix)
+		if w.hasSuffix("e") && w.in("e", w.rv) {
+			w.remove("e")
+		}
+	}
+	return true
+}
+
+// frResidualSuffix is step 4.
+func (w *snowballWord) frResidualSuffix() {
+	if w.hasSuffix("s") && len(w.rs) > 1 {
+		switch w.rs[len(w.rs)-2] {
+		case 'a', 'i', 'o', 'u', 'è', 's':
+		default:
+			w.remove("s")
+		}
+	}
+
+	suffix := w.longestIn(frResidual, w.rv)
+	switch suffix {
+	case "ion":
+		i := w.start(suffix) - 1
+		if w.in(suffix, w.r2) && i >= w.rv && (w.rs[i] == 's' || w.rs[i] == 't') {
+			w.remove(suffix)
+		}
+	case "ier", "ière", "Ier", "Ière":
+		w.replace(suffix, "i")
+	case "e":
+		w.remove(suffix)
+	case "ë":
+		if w.endsWith("gu", suffix) {
+			w.remove(suffix)
+		}
+	}
+}
+
+// frUndouble is step 5.
+func (w *snowballWord) frUndouble() {
+	for _, suffix := range []string{"enn", "onn", "ett", "ell", "eill"} {
+		if w.hasSuffix(suffix) {
+			w.rs = w.rs[:len(w.rs)-1]
+			return
+		}
+	}
+}
+
+// frUnaccent is step 6: é or è followed by at least one non-vowel at the
+// end of the word lose their accent.
+func (w *snowballWord) frUnaccent() {
+	i := len(w.rs) - 1
+	for i >= 0 && !w.isVowel(i) {
+		i--
+	}
+	if i < 0 || i == len(w.rs)-1 {
+		return
+	}
+	if w.rs[i] == 'é' || w.rs[i] == 'è' {
+		w.rs[i] = 'e'
+	}
+}
@@ -0,0 +1,120 @@
+package stemmer
+
+import (
+	"strings"
+)
+
+// Snowball German stemmer.
+// See https://snowballstem.org/algorithms/german/stemmer.html
+
+var (
+	deStep1 = []string{"em", "ern", "er", "e", "en", "es", "s"}
+	deStep2 = []string{"en", "er", "est", "st"}
+	deStep3 = []string{"end", "ung", "ig", "ik", "isch", "lich", "heit", "keit"}
+)
+
+func german(word string) string {
+	w := newSnowballWord(strings.ReplaceAll(word, "ß", "ss"), "aeiouyäöü")
+	w.dePrelude()
+	w.markGermanR1R2()
+
+	w.deStep1()
+	w.deStep2()
+	w.deStep3()
+
+	w.replaceRunes("UYäöü", "uyaou")
+	return w.String()
+}
+
+// dePrelude puts u and y between vowels into upper case.
+func (w *snowballWord) dePrelude() {
+	for i, r := range w.rs {
+		if (r == 'u' || r == 'y') && w.isVowel(i-1) && w.isVowel(i+1) {
+			w.rs[i] = r - 'a' + 'A'
+		}
+	}
+}
+
+// markGermanR1R2 sets R1 & R2, R1 is adjusted so the region before it
+// contains at least 3 letters.
+func (w *snowballWord) markGermanR1R2() {
+	w.markR1R2()
+	if w.r1 < 3 {
+		w.r1 = 3
+		if w.r1 > len(w.rs) {
+			w.r1 = len(w.rs)
+		}
+	}
+}
+
+// isSEnding reports if the rune before suffix is a valid s-ending.
+func (w *snowballWord) isSEnding(suffix string, endings string) bool {
+	i := w.start(suffix) - 1
+	return i >= 0 && strings.ContainsRune(endings, w.rs[i])
+}
+
+func (w *snowballWord) deStep1() {
+	suffix := w.longest(deStep1)
+	if suffix == "" || !w.in(suffix, w.r1) {
+		return
+	}
+
+	switch suffix {
+	case "em", "ern", "er":
+		w.remove(suffix)
+	case "e", "en", "es":
+		w.remove(suffix)
+		if w.hasSuffix("niss") {
+			w.remove("s")
+		}
+	case "s":
+		if w.isSEnding(suffix, "bdfghklmnrt") {
+			w.remove(suffix)
+		}
+	}
+}
+
+func (w *snowballWord) deStep2() {
+	suffix := w.longest(deStep2)
+	if suffix == "" || !w.in(suffix, w.r1) {
+		return
+	}
+
+	switch suffix {
+	case "en", "er", "est":
+		w.remove(suffix)
+	case "st":
+		if w.isSEnding(suffix, "bdfghklmnt") && w.start(suffix) >= 4 {
+			w.remove(suffix)
+		}
+	}
+}
+
+func (w *snowballWord) deStep3() {
+	suffix := w.longest(deStep3)
+	if suffix == "" || !w.in(suffix, w.r2) {
+		return
+	}
+
+	switch suffix {
+	case "end", "ung":
+		w.remove(suffix)
+		if w.hasSuffix("ig") && w.in("ig", w.r2) && !w.endsWith("e", "ig") {
+			w.remove("ig")
+		}
+	case "ig", "ik", "isch":
+		if !w.endsWith("e", suffix) {
+			w.remove(suffix)
+		}
+	case "lich", "heit":
+		w.remove(suffix)
+		if pre := w.longest([]string{"er", "en"}); pre != "" && w.in(pre, w.r1) {
+			w.remove(pre)
+		}
+	case "keit":
+		w.remove(suffix)
+		if pre := w.longest([]string{"lich", "ig"}); pre != "" && w.in(pre, w.r2) {
+			w.remove(pre)
+		}
+	}
+}
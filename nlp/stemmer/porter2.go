@@ -0,0 +1,396 @@
+package stemmer
+
+import (
+	"strings"
+)
+
+// Porter2 (a.k.a. Snowball English) stemmer.
+// See https://snowballstem.org/algorithms/english/stemmer.html
+
+var (
+	// exceptions are words that are not stemmed by the normal rules.
+	exceptions = map[string]string{
+		"skis":   "ski",
+		"skies":  "sky",
+		"dying":  "die",
+		"lying":  "lie",
+		"tying":  "tie",
+		"idly":   "idl",
+		"gently": "gentl",
+		"ugly":   "ugli",
+		"early":  "earli",
+		"only":   "onli",
+		"singly": "singl",
+		// invariant forms
+		"sky":    "sky",
+		"news":   "news",
+		"howe":   "howe",
+		"atlas":  "atlas",
+		"cosmos": "cosmos",
+		"bias":   "bias",
+		"andes":  "andes",
+	}
+
+	// postStep1a are left alone once step 1a is done.
+	postStep1a = map[string]bool{
+		"inning":  true,
+		"outing":  true,
+		"canning": true,
+		"herring": true,
+		"earring": true,
+		"proceed": true,
+		"exceed":  true,
+		"succeed": true,
+	}
+
+	// r1Prefixes have a fixed R1 region.
+	r1Prefixes = []string{"gener", "commun", "arsen"}
+
+	step1bSuffixes = []string{"eedly", "ingly", "edly", "eed", "ing", "ed"}
+
+	step2Suffixes = []struct {
+		suffix string
+		repl   string
+	}{
+		{"ization", "ize"},
+		{"ational", "ate"},
+		{"fulness", "ful"},
+		{"ousness", "ous"},
+		{"iveness", "ive"},
+		{"tional", "tion"},
+		{"biliti", "ble"},
+		{"lessli", "less"},
+		{"entli", "ent"},
+		{"ation", "ate"},
+		{"alism", "al"},
+		{"aliti", "al"},
+		{"ousli", "ous"},
+		{"iviti", "ive"},
+		{"fulli", "ful"},
+		{"enci", "ence"},
+		{"anci", "ance"},
+		{"abli", "able"},
+		{"izer", "ize"},
+		{"ator", "ate"},
+		{"alli", "al"},
+		{"bli", "ble"},
+		{"ogi", "og"},
+		{"li", ""},
+	}
+
+	step3Suffixes = []struct {
+		suffix string
+		repl   string
+	}{
+		{"ational", "ate"},
+		{"tional", "tion"},
+		{"alize", "al"},
+		{"icate", "ic"},
+		{"iciti", "ic"},
+		{"ative", ""},
+		{"ical", "ic"},
+		{"ness", ""},
+		{"ful", ""},
+	}
+
+	step4Suffixes = []string{
+		"ement", "ance", "ence", "able", "ible", "ment",
+		"ant", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+		"al", "er", "ic",
+	}
+)
+
+// english returns the Porter2 stem of a lower case word.
+func english(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	if stem, ok := exceptions[word]; ok {
+		return stem
+	}
+
+	w := &porter2Word{b: []byte(strings.TrimPrefix(word, "'"))}
+	w.markYs()
+	w.findRegions()
+
+	w.step0()
+	w.step1a()
+	if postStep1a[string(w.b)] {
+		return string(w.b)
+	}
+	w.step1b()
+	w.step1c()
+	w.step2()
+	w.step3()
+	w.step4()
+	w.step5()
+
+	return strings.ReplaceAll(string(w.b), "Y", "y")
+}
+
+type porter2Word struct {
+	b  []byte
+	r1 int
+	r2 int
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+func isDouble(s []byte) bool {
+	if len(s) < 2 {
+		return false
+	}
+	c := s[len(s)-1]
+	if c != s[len(s)-2] {
+		return false
+	}
+	switch c {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+func isLiEnding(c byte) bool {
+	return strings.IndexByte("cdeghkmnrt", c) != -1
+}
+
+// markYs sets initial y, or y after a vowel, to Y (a consonant).
+func (w *porter2Word) markYs() {
+	for i, c := range w.b {
+		if c == 'y' && (i == 0 || isVowel(w.b[i-1])) {
+			w.b[i] = 'Y'
+		}
+	}
+}
+
+// region returns the start of the region after the first non-vowel following
+// a vowel, starting the search at start.
+func region(b []byte, start int) int {
+	for i := start + 1; i < len(b); i++ {
+		if !isVowel(b[i]) && isVowel(b[i-1]) {
+			return i + 1
+		}
+	}
+	return len(b)
+}
+
+func (w *porter2Word) findRegions() {
+	w.r1 = -1
+	for _, prefix := range r1Prefixes {
+		if strings.HasPrefix(string(w.b), prefix) {
+			w.r1 = len(prefix)
+			break
+		}
+	}
+	if w.r1 == -1 {
+		w.r1 = region(w.b, 0)
+	}
+	w.r2 = region(w.b, w.r1)
+}
+
+func (w *porter2Word) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(w.b), suffix)
+}
+
+// longest returns the longest suffix from suffixes the word ends with.
+// suffixes must be sorted by length, longest first.
+func (w *porter2Word) longest(suffixes []string) string {
+	for _, suffix := range suffixes {
+		if w.hasSuffix(suffix) {
+			return suffix
+		}
+	}
+	return ""
+}
+
+// inR1 reports if suffix is in the R1 region.
+func (w *porter2Word) inR1(suffix string) bool {
+	return len(w.b)-len(suffix) >= w.r1
+}
+
+// inR2 reports if suffix is in the R2 region.
+func (w *porter2Word) inR2(suffix string) bool {
+	return len(w.b)-len(suffix) >= w.r2
+}
+
+func (w *porter2Word) replace(suffix, repl string) {
+	w.b = append(w.b[:len(w.b)-len(suffix)], repl...)
+}
+
+// endsShortSyllable reports if b ends with a short syllable: a vowel followed
+// by a non-vowel other than w, x or Y and preceded by a non-vowel, or a vowel
+// at the beginning of the word followed by a non-vowel.
+func endsShortSyllable(b []byte) bool {
+	n := len(b)
+	if n == 2 {
+		return isVowel(b[0]) && !isVowel(b[1])
+	}
+	if n < 3 {
+		return false
+	}
+	c := b[n-1]
+	if isVowel(c) || c == 'w' || c == 'x' || c == 'Y' {
+		return false
+	}
+	return isVowel(b[n-2]) && !isVowel(b[n-3])
+}
+
+// isShort reports if the word ends in a short syllable and R1 is empty.
+func (w *porter2Word) isShort() bool {
+	return w.r1 >= len(w.b) && endsShortSyllable(w.b)
+}
+
+func (w *porter2Word) step0() {
+	for _, suffix := range []string{"'s'", "'s", "'"} {
+		if w.hasSuffix(suffix) {
+			w.replace(suffix, "")
+			return
+		}
+	}
+}
+
+func (w *porter2Word) step1a() {
+	switch {
+	case w.hasSuffix("sses"):
+		w.replace("sses", "ss")
+	case w.hasSuffix("ied"), w.hasSuffix("ies"):
+		if len(w.b) > 4 {
+			w.replace("ies", "i") // ied & ies have the same length
+		} else {
+			w.replace("ies", "ie")
+		}
+	case w.hasSuffix("us"), w.hasSuffix("ss"):
+		// nothing
+	case w.hasSuffix("s") && len(w.b) > 2:
+		// delete if there's a vowel before the letter preceding the s
+		for _, c := range w.b[:len(w.b)-2] {
+			if isVowel(c) {
+				w.replace("s", "")
+				break
+			}
+		}
+	}
+}
+
+func (w *porter2Word) step1b() {
+	suffix := w.longest(step1bSuffixes)
+	switch suffix {
+	case "":
+		return
+	case "eed", "eedly":
+		if w.inR1(suffix) {
+			w.replace(suffix, "ee")
+		}
+		return
+	}
+
+	stem := w.b[:len(w.b)-len(suffix)]
+	hasVowel := false
+	for _, c := range stem {
+		if isVowel(c) {
+			hasVowel = true
+			break
+		}
+	}
+	if !hasVowel {
+		return
+	}
+
+	w.replace(suffix, "")
+	switch {
+	case w.hasSuffix("at"), w.hasSuffix("bl"), w.hasSuffix("iz"):
+		w.b = append(w.b, 'e')
+	case isDouble(w.b):
+		w.b = w.b[:len(w.b)-1]
+	case w.isShort():
+		w.b = append(w.b, 'e')
+	}
+}
+
+func (w *porter2Word) step1c() {
+	n := len(w.b)
+	if n < 3 {
+		return
+	}
+	if c := w.b[n-1]; c != 'y' && c != 'Y' {
+		return
+	}
+	if !isVowel(w.b[n-2]) {
+		w.b[n-1] = 'i'
+	}
+}
+
+func (w *porter2Word) step2() {
+	for _, s := range step2Suffixes {
+		if !w.hasSuffix(s.suffix) {
+			continue
+		}
+		if !w.inR1(s.suffix) {
+			return
+		}
+
+		n := len(w.b) - len(s.suffix)
+		switch s.suffix {
+		case "ogi":
+			if n == 0 || w.b[n-1] != 'l' {
+				return
+			}
+		case "li":
+			if n == 0 || !isLiEnding(w.b[n-1]) {
+				return
+			}
+		}
+		w.replace(s.suffix, s.repl)
+		return
+	}
+}
+
+func (w *porter2Word) step3() {
+	for _, s := range step3Suffixes {
+		if !w.hasSuffix(s.suffix) {
+			continue
+		}
+		if !w.inR1(s.suffix) {
+			return
+		}
+		if s.suffix == "ative" && !w.inR2(s.suffix) {
+			return
+		}
+		w.replace(s.suffix, s.repl)
+		return
+	}
+}
+
+func (w *porter2Word) step4() {
+	suffix := w.longest(step4Suffixes)
+	if suffix == "" || !w.inR2(suffix) {
+		return
+	}
+	if suffix == "ion" {
+		n := len(w.b) - len(suffix)
+		if n == 0 || (w.b[n-1] != 's' && w.b[n-1] != 't') {
+			return
+		}
+	}
+	w.replace(suffix, "")
+}
+
+func (w *porter2Word) step5() {
+	switch {
+	case w.hasSuffix("e"):
+		if w.inR2("e") || (w.inR1("e") && !endsShortSyllable(w.b[:len(w.b)-1])) {
+			w.replace("e", "")
+		}
+	case w.hasSuffix("l"):
+		if w.inR2("l") && w.hasSuffix("ll") {
+			w.replace("l", "")
+		}
+	}
+}
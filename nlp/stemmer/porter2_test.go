@@ -0,0 +1,80 @@
+package stemmer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStem(t *testing.T) {
+	for _, tc := range loadStemCases(t, "testdata/porter2.txt") {
+		require.Equal(t, tc.stem, Stem(tc.word), tc.word)
+	}
+}
+
+// stemExamples are not in the Snowball vocabulary: words of the exceptions
+// lists, examples of the algorithm steps (some are already step 1 output, like
+// "valenci") and words we index often.
+var stemExamples = []stemCase{
+	// exceptions and R1 prefixes
+	{"skis", "ski"},
+	{"howe", "howe"},
+	{"atlas", "atlas"},
+	{"cosmos", "cosmos"},
+	{"inning", "inning"},
+	{"outing", "outing"},
+	{"outings", "outing"},
+	{"herring", "herring"},
+	{"arsenal", "arsenal"},
+	// step 1a & 1b
+	{"kiwis", "kiwi"},
+	{"motoring", "motor"},
+	{"conflated", "conflat"},
+	{"fizzed", "fizz"},
+	{"controll", "control"},
+	// step 2
+	{"relational", "relat"},
+	{"valenci", "valenc"},
+	{"digitizer", "digit"},
+	{"conformabli", "conform"},
+	{"radicalli", "radic"},
+	{"differentli", "differ"},
+	{"vileli", "vile"},
+	{"analogousli", "analog"},
+	{"vietnamization", "vietnam"},
+	{"predication", "predic"},
+	{"feudalism", "feudal"},
+	{"decisiveness", "decis"},
+	{"formaliti", "formal"},
+	{"sensitiviti", "sensit"},
+	{"sensibiliti", "sensibl"},
+	// step 3
+	{"triplicate", "triplic"},
+	{"formative", "format"},
+	{"formalize", "formal"},
+	{"electriciti", "electr"},
+	// step 4
+	{"airliner", "airlin"},
+	{"gyroscopic", "gyroscop"},
+	{"adjustable", "adjust"},
+	{"defensible", "defens"},
+	{"irritant", "irrit"},
+	{"replacement", "replac"},
+	{"activate", "activ"},
+	{"angulariti", "angular"},
+	{"homologous", "homolog"},
+	{"bowdlerize", "bowdler"},
+	{"probate", "probat"},
+	// contractions and names
+	{"who's", "who"},
+	{"what's", "what"},
+	{"sherlock", "sherlock"},
+	{"holmes", "holm"},
+	{"watson", "watson"},
+}
+
+func TestStemExamples(t *testing.T) {
+	for _, tc := range stemExamples {
+		require.Equal(t, tc.stem, Stem(tc.word), tc.word)
+	}
+}
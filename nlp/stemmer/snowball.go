@@ -0,0 +1,123 @@
+package stemmer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Helpers shared by the Snowball stemmers of non-English languages.
+// See https://snowballstem.org/texts/r1r2.html for regions definitions.
+
+// snowballWord is a word being stemmed. Regions are rune indices, the
+// regions are computed once and don't move when suffixes are removed.
+type snowballWord struct {
+	rs     []rune
+	vowels string
+	rv     int
+	r1     int
+	r2     int
+}
+
+func newSnowballWord(word, vowels string) *snowballWord {
+	return &snowballWord{rs: []rune(word), vowels: vowels}
+}
+
+func (w *snowballWord) String() string {
+	return string(w.rs)
+}
+
+func (w *snowballWord) isVowel(i int) bool {
+	return i >= 0 && i < len(w.rs) && strings.ContainsRune(w.vowels, w.rs[i])
+}
+
+// region returns the start of the region after the first non-vowel following
+// a vowel, starting the search at start.
+func (w *snowballWord) region(start int) int {
+	for i := start + 1; i < len(w.rs); i++ {
+		if !w.isVowel(i) && w.isVowel(i-1) {
+			return i + 1
+		}
+	}
+	return len(w.rs)
+}
+
+// pastVowel returns the index after the first vowel at or after start.
+func (w *snowballWord) pastVowel(start int) int {
+	for i := start; i < len(w.rs); i++ {
+		if w.isVowel(i) {
+			return i + 1
+		}
+	}
+	return len(w.rs)
+}
+
+// pastNonVowel returns the index after the first non-vowel at or after start.
+func (w *snowballWord) pastNonVowel(start int) int {
+	for i := start; i < len(w.rs); i++ {
+		if !w.isVowel(i) {
+			return i + 1
+		}
+	}
+	return len(w.rs)
+}
+
+func (w *snowballWord) markR1R2() {
+	w.r1 = w.region(0)
+	w.r2 = w.region(w.r1)
+}
+
+func (w *snowballWord) hasSuffix(suffix string) bool {
+	n := utf8.RuneCountInString(suffix)
+	if n > len(w.rs) {
+		return false
+	}
+	return string(w.rs[len(w.rs)-n:]) == suffix
+}
+
+// start returns the index where suffix starts.
+func (w *snowballWord) start(suffix string) int {
+	return len(w.rs) - utf8.RuneCountInString(suffix)
+}
+
+// longest returns the longest suffix in suffixes the word ends with.
+func (w *snowballWord) longest(suffixes []string) string {
+	return w.longestIn(suffixes, 0)
+}
+
+// longestIn returns the longest suffix in suffixes that the word ends with
+// and that starts at or after limit.
+func (w *snowballWord) longestIn(suffixes []string, limit int) string {
+	found := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(found) && w.hasSuffix(suffix) && w.start(suffix) >= limit {
+			found = suffix
+		}
+	}
+	return found
+}
+
+// in reports if suffix is inside the region starting at region.
+func (w *snowballWord) in(suffix string, region int) bool {
+	return w.start(suffix) >= region
+}
+
+// endsWith reports if the word ends with suffix followed by tail.
+func (w *snowballWord) endsWith(suffix, tail string) bool {
+	return w.hasSuffix(suffix + tail)
+}
+
+func (w *snowballWord) replace(suffix, repl string) {
+	w.rs = append(w.rs[:w.start(suffix)], []rune(repl)...)
+}
+
+func (w *snowballWord) remove(suffix string) {
+	w.replace(suffix, "")
+}
+
+func (w *snowballWord) replaceRunes(from, to string) {
+	for i, r := range w.rs {
+		if j := strings.IndexRune(from, r); j != -1 {
+			w.rs[i] = []rune(to)[utf8.RuneCountInString(from[:j])]
+		}
+	}
+}
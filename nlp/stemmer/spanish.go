@@ -0,0 +1,247 @@
+package stemmer
+
+// Snowball Spanish stemmer.
+// See https://snowballstem.org/algorithms/spanish/stemmer.html
+
+var (
+	esPronouns = []string{
+		"me", "se", "sela", "selo", "selas", "selos", "la", "le", "lo",
+		"las", "les", "los", "nos",
+	}
+
+	// esPronounVerbs are the verb endings that may precede a pronoun,
+	// mapped to their unaccented form.
+	esPronounVerbs = map[string]string{
+		"iéndo": "iendo",
+		"ándo":  "ando",
+		"ár":    "ar",
+		"ér":    "er",
+		"ír":    "ir",
+		"ando":  "ando",
+		"iendo": "iendo",
+		"ar":    "ar",
+		"er":    "er",
+		"ir":    "ir",
+		"yendo": "yendo",
+	}
+
+	esStep1 = struct {
+		delete, ation, logia, ucion, encia, amente, mente, idad, iva []string
+	}{
+		delete: []string{
+			"anza", "anzas", "ico", "ica", "icos", "icas", "ismo", "ismos",
+			"able", "ables", "ible", "ibles", "ista", "istas", "oso", "osa",
+			"osos", "osas", "amiento", "amientos", "imiento", "imientos",
+		},
+		ation: []string{
+			"adora", "ador", "ación", "adoras", "adores", "aciones", "ante",
+			"antes", "ancia", "ancias",
+		},
+		logia:  []string{"logía", "logías"},
+		ucion:  []string{"ución", "uciones"},
+		encia:  []string{"encia", "encias"},
+		amente: []string{"amente"},
+		mente:  []string{"mente"},
+		idad:   []string{"idad", "idades"},
+		iva:    []string{"iva", "ivo", "ivas", "ivos"},
+	}
+
+	esYVerbs = []string{
+		"ya", "ye", "yan", "yen", "yeron", "yendo", "yo", "yó", "yas", "yes",
+		"yais", "yamos",
+	}
+
+	esVerbsGu = []string{"en", "es", "éis", "emos"}
+
+	esVerbs = []string{
+		"arían", "arías", "arán", "arás", "aríais", "aría", "aréis",
+		"aríamos", "aremos", "ará", "aré", "erían", "erías", "erán",
+		"erás", "eríais", "ería", "eréis", "eríamos", "eremos", "erá",
+		"eré", "irían", "irías", "irán", "irás", "iríais", "iría", "iréis",
+		"iríamos", "iremos", "irá", "iré", "aba", "ada", "ida", "ía", "ara",
+		"iera", "ad", "ed", "id", "ase", "iese", "aste", "iste", "an",
+		"aban", "ían", "aran", "ieran", "asen", "iesen", "aron", "ieron",
+		"ado", "ido", "ando", "iendo", "ió", "ar", "er", "ir", "as", "abas",
+		"adas", "idas", "ías", "aras", "ieras", "ases", "ieses", "ís", "áis",
+		"abais", "íais", "arais", "ierais", "aseis", "ieseis", "asteis",
+		"isteis", "ados", "idos", "amos", "ábamos", "íamos", "imos",
+		"áramos", "iéramos", "iésemos", "ásemos",
+	}
+
+	esResidual = []string{"os", "a", "o", "á", "í", "ó", "e", "é"}
+)
+
+func spanish(word string) string {
+	w := newSnowballWord(word, "aeiouáéíóúü")
+	w.markSpanishRV()
+	w.markR1R2()
+
+	w.esPronoun()
+	if !w.esStandardSuffix() {
+		if !w.esYVerbSuffix() {
+			w.esVerbSuffix()
+		}
+	}
+	w.esResidualSuffix()
+
+	w.replaceRunes("áéíóú", "aeiou")
+	return w.String()
+}
+
+// markSpanishRV sets RV: if the second letter is a consonant, RV is the region
+// after the next following vowel, if the first two letters are vowels, RV is
+// the region after the next consonant, otherwise RV is the region after the
+// third letter.
+func (w *snowballWord) markSpanishRV() {
+	switch {
+	case len(w.rs) < 2:
+		w.rv = len(w.rs)
+	case !w.isVowel(1):
+		w.rv = w.pastVowel(2)
+	case w.isVowel(0):
+		w.rv = w.pastNonVowel(2)
+	default:
+		w.rv = 3
+	}
+	if w.rv > len(w.rs) {
+		w.rv = len(w.rs)
+	}
+}
+
+// esPronoun removes attached pronouns ("dándole" -> "dando").
+func (w *snowballWord) esPronoun() {
+	pronoun := w.longest(esPronouns)
+	if pronoun == "" {
+		return
+	}
+
+	verb := ""
+	for ending := range esPronounVerbs {
+		if len(ending) > len(verb) && w.endsWith(ending, pronoun) {
+			verb = ending
+		}
+	}
+	if verb == "" || w.start(verb+pronoun) < w.rv {
+		return
+	}
+
+	if verb == "yendo" {
+		if w.endsWith("u", verb+pronoun) {
+			w.remove(pronoun)
+		}
+		return
+	}
+	w.replace(verb+pronoun, esPronounVerbs[verb])
+}
+
+// esStandardSuffix is step 1, it reports if a suffix was removed.
+func (w *snowballWord) esStandardSuffix() bool {
+	s := esStep1
+	suffix := w.longest(concat(s.delete, s.ation, s.logia, s.ucion, s.encia, s.amente, s.mente, s.idad, s.iva))
+	if suffix == "" {
+		return false
+	}
+
+	switch {
+	case contains(s.amente, suffix):
+		if !w.in(suffix, w.r1) {
+			return false
+		}
+		w.remove(suffix)
+		if pre := w.longest([]string{"iv", "os", "ic", "ad"}); pre != "" && w.in(pre, w.r2) {
+			w.remove(pre)
+			if pre == "iv" && w.hasSuffix("at") && w.in("at", w.r2) {
+				w.remove("at")
+			}
+		}
+		return true
+	case !w.in(suffix, w.r2):
+		return false
+	}
+
+	switch {
+	case contains(s.delete, suffix):
+		w.remove(suffix)
+	case contains(s.ation, suffix):
+		w.remove(suffix)
+		w.removeInR2("ic")
+	case contains(s.logia, suffix):
+		w.replace(suffix, "log")
+	case contains(s.ucion, suffix):
+		w.replace(suffix, "u")
+	case contains(s.encia, suffix):
+		w.replace(suffix, "ente")
+	case contains(s.mente, suffix):
+		w.remove(suffix)
+		if pre := w.longest([]string{"ante", "able", "ible"}); pre != "" {
+			w.removeInR2(pre)
+		}
+	case contains(s.idad, suffix):
+		w.remove(suffix)
+		if pre := w.longest([]string{"abil", "ic", "iv"}); pre != "" {
+			w.removeInR2(pre)
+		}
+	case contains(s.iva, suffix):
+		w.remove(suffix)
+		w.removeInR2("at")
+	}
+	return true
+}
+
+// esYVerbSuffix is step 2a, it reports if a suffix was removed.
+func (w *snowballWord) esYVerbSuffix() bool {
+	suffix := w.longestIn(esYVerbs, w.rv)
+	if suffix == "" || !w.endsWith("u", suffix) {
+		return false
+	}
+	w.remove(suffix)
+	return true
+}
+
+// esVerbSuffix is step 2b.
+func (w *snowballWord) esVerbSuffix() {
+	suffix := w.longestIn(concat(esVerbsGu, esVerbs), w.rv)
+	if suffix == "" {
+		return
+	}
+	w.remove(suffix)
+	if contains(esVerbsGu, suffix) && w.hasSuffix("gu") {
+		w.remove("u")
+	}
+}
+
+// esResidualSuffix is step 3.
+func (w *snowballWord) esResidualSuffix() {
+	suffix := w.longest(esResidual)
+	if suffix == "" || !w.in(suffix, w.rv) {
+		return
+	}
+	w.remove(suffix)
+	if (suffix == "e" || suffix == "é") && w.hasSuffix("gu") && w.in("u", w.rv) {
+		w.remove("u")
+	}
+}
+
+// removeInR2 removes suffix if the word ends with it inside R2.
+func (w *snowballWord) removeInR2(suffix string) {
+	if w.hasSuffix(suffix) && w.in(suffix, w.r2) {
+		w.remove(suffix)
+	}
+}
+
+func concat(lists ...[]string) []string {
+	var out []string
+	for _, l := range lists {
+		out = append(out, l...)
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
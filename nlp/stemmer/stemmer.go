@@ -1,18 +1,70 @@
 package stemmer
 
 import (
-	"strings"
+	"fmt"
+	"sort"
+	"sync"
 )
 
+// Stemmer reduces a lower case word to its stem.
+type Stemmer interface {
+	Stem(word string) string
+}
+
+// Func is an adapter to allow the use of ordinary functions as a Stemmer.
+type Func func(word string) string
+
+// Stem returns f(word).
+func (f Func) Stem(word string) string {
+	return f(word)
+}
+
 var (
-	suffixes = []string{"s", "ing", "ed"}
+	registryMu sync.RWMutex
+	registry   = map[string]Stemmer{
+		"en":   Func(english),
+		"de":   Func(german),
+		"es":   Func(spanish),
+		"fr":   Func(french),
+		"none": Func(func(word string) string { return word }),
+	}
 )
 
-func Stem(word string) string {
-	for _, suffix := range suffixes {
-		if strings.HasSuffix(word, suffix) {
-			return word[:len(word)-len(suffix)]
-		}
+// Register registers a stemmer for a language code (e.g. "it").
+// It replaces the existing stemmer for lang, if any.
+func Register(lang string, s Stemmer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[lang] = s
+}
+
+// ForLanguage returns the stemmer registered for lang.
+func ForLanguage(lang string) (Stemmer, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	s, ok := registry[lang]
+	if !ok {
+		return nil, fmt.Errorf("no stemmer for language %q", lang)
 	}
-	return word
+	return s, nil
+}
+
+// Languages returns the sorted list of registered language codes.
+func Languages() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	langs := make([]string, 0, len(registry))
+	for lang := range registry {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Stem returns the English (Porter2) stem of a lower case word.
+func Stem(word string) string {
+	return english(word)
 }
@@ -0,0 +1,80 @@
+package stemmer
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stemCase struct {
+	word string
+	stem string
+}
+
+// loadStemCases loads "word stem" pairs from a Snowball style vocabulary file.
+func loadStemCases(t *testing.T, fileName string) []stemCase {
+	file, err := os.Open(fileName)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var cases []stemCase
+	s := bufio.NewScanner(file)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		require.Len(t, fields, 2, "bad line: %q", line)
+		cases = append(cases, stemCase{fields[0], fields[1]})
+	}
+	require.NoError(t, s.Err())
+	return cases
+}
+
+func TestForLanguage(t *testing.T) {
+	langs := map[string]string{
+		"de": "testdata/german.txt",
+		"en": "testdata/porter2.txt",
+		"es": "testdata/spanish.txt",
+		"fr": "testdata/french.txt",
+	}
+	for lang, fileName := range langs {
+		s, err := ForLanguage(lang)
+		require.NoError(t, err)
+		for _, tc := range loadStemCases(t, fileName) {
+			t.Run(lang+"/"+tc.word, func(t *testing.T) {
+				require.Equal(t, tc.stem, s.Stem(tc.word))
+			})
+		}
+	}
+}
+
+func TestForLanguageNone(t *testing.T) {
+	s, err := ForLanguage("none")
+	require.NoError(t, err)
+	require.Equal(t, "running", s.Stem("running"))
+
+	_, err = ForLanguage("xx")
+	require.Error(t, err)
+}
+
+// unregister removes the stemmer registered for lang.
+func unregister(lang string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, lang)
+}
+
+func TestRegister(t *testing.T) {
+	Register("xx", Func(strings.ToUpper))
+	t.Cleanup(func() { unregister("xx") })
+	s, err := ForLanguage("xx")
+	require.NoError(t, err)
+	require.Equal(t, "GO", s.Stem("go"))
+	require.Contains(t, Languages(), "xx")
+}
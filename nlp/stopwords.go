@@ -0,0 +1,71 @@
+package nlp
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/text/cases"
+)
+
+//go:embed stopwords/*.txt
+var stopWordsFS embed.FS
+
+// StopWords is a set of (case folded) stop words.
+type StopWords map[string]bool
+
+// Contains reports if word is a stop word.
+func (s StopWords) Contains(word string) bool {
+	return s[word]
+}
+
+// Merge adds the words of other to s.
+func (s StopWords) Merge(other StopWords) {
+	for w := range other {
+		s[w] = true
+	}
+}
+
+// LoadStopWords reads stop words from r, one word per line.
+// Empty lines and lines starting with # are ignored.
+func LoadStopWords(r io.Reader) (StopWords, error) {
+	fold := cases.Fold()
+	words := make(StopWords)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words[apostrophes.Replace(fold.String(line))] = true
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// LoadStopWordsFile reads stop words from a file, see LoadStopWords.
+func LoadStopWordsFile(fileName string) (StopWords, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadStopWords(file)
+}
+
+// BuiltinStopWords returns the built-in stop words for lang (e.g. "en").
+func BuiltinStopWords(lang string) (StopWords, error) {
+	file, err := stopWordsFS.Open("stopwords/" + lang + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("no stop words for language %q", lang)
+	}
+	defer file.Close()
+
+	return LoadStopWords(file)
+}
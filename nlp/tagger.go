@@ -0,0 +1,373 @@
+package nlp
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:generate go run ./internal/traintagger -o taggerdata/en.model taggerdata/en.txt
+
+// Tagger is an averaged perceptron part-of-speech tagger
+// (https://explosion.ai/blog/part-of-speech-pos-tagger-in-python).
+// The built-in English tagger uses the Universal Dependencies tags (NOUN,
+// VERB, ADJ ...), see https://universaldependencies.org/u/pos/.
+//
+// A Tagger is made by TrainTagger, ReadFrom or EnglishTagger, the zero
+// Tagger has no tags. A Tagger is safe for concurrent use, except for ReadFrom.
+type Tagger struct {
+	tags    []string             // sorted
+	words   map[string]int       // unambiguous words -> tag
+	weights map[string][]float64 // feature -> weight per tag
+}
+
+// TaggedSentence is a sentence with the tag of every word, used for training.
+type TaggedSentence struct {
+	Words []string
+	Tags  []string
+}
+
+// Tagger training parameters
+const (
+	// Minimal number of occurrences of a word with a single tag to be
+	// tagged without the model
+	unambiguousCount = 5
+	// Minimal ratio of the most common tag of a word
+	unambiguousRatio = 0.98
+)
+
+var (
+	//go:embed taggerdata/en.model
+	englishModel []byte
+
+	englishTaggerOnce sync.Once
+	englishTagger     *Tagger
+)
+
+// EnglishTagger returns the built-in English tagger, trained on a small hand
+// tagged corpus (taggerdata/en.txt). Its words are tokens as returned by the
+// tokenizer with contractions (see WithContractions).
+func EnglishTagger() *Tagger {
+	englishTaggerOnce.Do(func() {
+		englishTagger = &Tagger{}
+		if err := englishTagger.decode(englishModel); err != nil {
+			panic(err) // embedded, can't happen
+		}
+	})
+	return englishTagger
+}
+
+// Tags returns the tags used by the tagger.
+func (tg *Tagger) Tags() []string {
+	return append([]string(nil), tg.tags...)
+}
+
+// Tag returns the part-of-speech tag of every token, by its surface form
+// (Text) since stemming and case folding lose information. For best
+// results, tag a sentence at a time (see Sentences).
+func (tg *Tagger) Tag(tokens []Token) []string {
+	words := make([]string, len(tokens))
+	for i, tok := range tokens {
+		words[i] = tok.Text
+	}
+	return tg.TagWords(words)
+}
+
+// TagWords returns the part-of-speech tag of every word. The tags are empty
+// if the tagger has no tags (the zero Tagger).
+func (tg *Tagger) TagWords(words []string) []string {
+	tags := make([]string, len(words))
+	if len(tg.tags) == 0 {
+		return tags
+	}
+
+	ctx := tagContext(words)
+	prev, prev2 := "-START-", "-START2-"
+	for i := range words {
+		tag, ok := tg.words[ctx[i+2]]
+		if !ok {
+			tag = tg.predict(tagFeatures(words, ctx, i, prev, prev2))
+		}
+		tags[i] = tg.tags[tag]
+		prev, prev2 = tags[i], prev
+	}
+	return tags
+}
+
+// predict returns the tag with the highest score for features.
+func (tg *Tagger) predict(features []string) int {
+	scores := make([]float64, len(tg.tags))
+	for _, f := range features {
+		for tag, w := range tg.weights[f] {
+			scores[tag] += w
+		}
+	}
+
+	best := 0
+	for tag, score := range scores {
+		if score > scores[best] {
+			best = tag
+		}
+	}
+	return best
+}
+
+// tagContext returns the normalized words, padded with two start and two end
+// markers.
+func tagContext(words []string) []string {
+	ctx := make([]string, 0, len(words)+4)
+	ctx = append(ctx, "-START-", "-START2-")
+	for _, w := range words {
+		ctx = append(ctx, normTagWord(w))
+	}
+	return append(ctx, "-END-", "-END2-")
+}
+
+// normTagWord returns the lower case word, numbers are replaced by "!YEAR"
+// (4 digits) or "!NUM".
+func normTagWord(word string) string {
+	if word != "" && wordType([]byte(word)) == NumberToken {
+		if len(word) == 4 && strings.Trim(word, "0123456789") == "" {
+			return "!YEAR"
+		}
+		return "!NUM"
+	}
+	return apostrophes.Replace(strings.ToLower(word))
+}
+
+// shape returns the case shape of a word: "Xx" (title), "X" (upper), "x"
+// (lower) or "-" (other).
+func shape(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	switch {
+	case !unicode.IsLetter(r):
+		return "-"
+	case unicode.IsLower(r):
+		return "x"
+	case strings.ToUpper(word[size:]) == word[size:] && size < len(word):
+		return "X"
+	}
+	return "Xx"
+}
+
+// prefix returns the first rune of word.
+func prefix(word string) string {
+	_, size := utf8.DecodeRuneInString(word)
+	return word[:size]
+}
+
+// suffix returns the last 3 runes of word.
+func suffix(word string) string {
+	rs := []rune(word)
+	if len(rs) > 3 {
+		rs = rs[len(rs)-3:]
+	}
+	return string(rs)
+}
+
+// tagFeatures returns the features of words[i], prev and prev2 are the tags of
+// the previous words.
+func tagFeatures(words, ctx []string, i int, prev, prev2 string) []string {
+	w := ctx[i+2]
+	first := ""
+	if i == 0 {
+		first = " first"
+	}
+	return []string{
+		"bias",
+		"w " + w,
+		"suffix " + suffix(w),
+		"prefix " + prefix(w),
+		"shape " + shape(words[i]) + first,
+		"t-1 " + prev,
+		"t-2 " + prev2,
+		"t-1 t-2 " + prev + " " + prev2,
+		"t-1 w " + prev + " " + w,
+		"w-1 " + ctx[i+1],
+		"suffix-1 " + suffix(ctx[i+1]),
+		"w-2 " + ctx[i],
+		"w+1 " + ctx[i+3],
+		"suffix+1 " + suffix(ctx[i+3]),
+		"w+2 " + ctx[i+4],
+	}
+}
+
+// ReadTaggedSentences reads training sentences from r: one sentence per
+// line, words are followed by their tag ("The/DET cat/NOUN sat/VERB").
+// Empty lines and lines starting with # are ignored.
+func ReadTaggedSentences(r io.Reader) ([]TaggedSentence, error) {
+	var sentences []TaggedSentence
+	s := bufio.NewScanner(r)
+	lnum := 0
+	for s.Scan() {
+		lnum++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var sent TaggedSentence
+		for _, field := range strings.Fields(line) {
+			i := strings.LastIndexByte(field, '/')
+			if i <= 0 || i == len(field)-1 {
+				return nil, fmt.Errorf("%d: bad tagged word: %q", lnum, field)
+			}
+			sent.Words = append(sent.Words, field[:i])
+			sent.Tags = append(sent.Tags, field[i+1:])
+		}
+		sentences = append(sentences, sent)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return sentences, nil
+}
+
+// TrainTagger returns a tagger trained on sentences, iterations is the number
+// of passes over the sentences (5 to 10 is usually enough). Training is
+// deterministic: the same sentences give the same tagger.
+func TrainTagger(sentences []TaggedSentence, iterations int) (*Tagger, error) {
+	tagSet := make(map[string]bool)
+	for i, sent := range sentences {
+		if len(sent.Words) != len(sent.Tags) {
+			return nil, fmt.Errorf("sentence %d: %d words but %d tags", i, len(sent.Words), len(sent.Tags))
+		}
+		for _, tag := range sent.Tags {
+			tagSet[tag] = true
+		}
+	}
+	if len(tagSet) == 0 {
+		return nil, fmt.Errorf("no tags")
+	}
+
+	tg := &Tagger{words: make(map[string]int)}
+	for tag := range tagSet {
+		tg.tags = append(tg.tags, tag)
+	}
+	sort.Strings(tg.tags)
+	tagIDs := make(map[string]int, len(tg.tags))
+	for i, tag := range tg.tags {
+		tagIDs[tag] = i
+	}
+
+	tg.findUnambiguous(sentences, tagIDs)
+
+	p := perceptron{
+		numTags: len(tg.tags),
+		weights: make(map[string][]float64),
+		totals:  make(map[string][]float64),
+		stamps:  make(map[string][]int),
+	}
+	tg.weights = p.weights
+
+	order := append([]TaggedSentence(nil), sentences...)
+	rnd := rand.New(rand.NewSource(1))
+	for iter := 0; iter < iterations; iter++ {
+		for _, sent := range order {
+			ctx := tagContext(sent.Words)
+			prev, prev2 := "-START-", "-START2-"
+			for i := range sent.Words {
+				truth := tagIDs[sent.Tags[i]]
+				guess, ok := tg.words[ctx[i+2]]
+				if !ok {
+					features := tagFeatures(sent.Words, ctx, i, prev, prev2)
+					guess = tg.predict(features)
+					p.update(truth, guess, features)
+				}
+				prev, prev2 = tg.tags[guess], prev
+			}
+		}
+		rnd.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+
+	tg.weights = p.average()
+	return tg, nil
+}
+
+// findUnambiguous finds the frequent words that always have the same tag.
+func (tg *Tagger) findUnambiguous(sentences []TaggedSentence, tagIDs map[string]int) {
+	counts := make(map[string][]int) // word -> tag -> count
+	for _, sent := range sentences {
+		for i, word := range sent.Words {
+			w := normTagWord(word)
+			if counts[w] == nil {
+				counts[w] = make([]int, len(tagIDs))
+			}
+			counts[w][tagIDs[sent.Tags[i]]]++
+		}
+	}
+
+	for w, tagCounts := range counts {
+		best, total := 0, 0
+		for tag, n := range tagCounts {
+			total += n
+			if n > tagCounts[best] {
+				best = tag
+			}
+		}
+		if total >= unambiguousCount && float64(tagCounts[best])/float64(total) >= unambiguousRatio {
+			tg.words[w] = best
+		}
+	}
+}
+
+// perceptron is the averaged perceptron used in training.
+type perceptron struct {
+	numTags int
+	weights map[string][]float64 // feature -> tag -> weight
+	totals  map[string][]float64 // feature -> tag -> accumulated weight
+	stamps  map[string][]int     // feature -> tag -> last update
+	n       int                  // number of updates
+}
+
+func (p *perceptron) update(truth, guess int, features []string) {
+	p.n++
+	if truth == guess {
+		return
+	}
+
+	for _, f := range features {
+		if p.weights[f] == nil {
+			p.weights[f] = make([]float64, p.numTags)
+			p.totals[f] = make([]float64, p.numTags)
+			p.stamps[f] = make([]int, p.numTags)
+		}
+		p.add(f, truth, 1)
+		p.add(f, guess, -1)
+	}
+}
+
+func (p *perceptron) add(f string, tag int, v float64) {
+	w := p.weights[f]
+	p.totals[f][tag] += float64(p.n-p.stamps[f][tag]) * w[tag]
+	p.stamps[f][tag] = p.n
+	w[tag] += v
+}
+
+// average returns the averaged weights, rounded to float32 (as stored in
+// tagger files). Features with all weights zero are dropped.
+func (p *perceptron) average() map[string][]float64 {
+	weights := make(map[string][]float64, len(p.weights))
+	for f, w := range p.weights {
+		avg := make([]float64, p.numTags)
+		nonZero := false
+		for tag := range w {
+			total := p.totals[f][tag] + float64(p.n-p.stamps[f][tag])*w[tag]
+			avg[tag] = float64(float32(total / float64(p.n)))
+			if avg[tag] != 0 {
+				nonZero = true
+			}
+		}
+		if nonZero {
+			weights[f] = avg
+		}
+	}
+	return weights
+}
@@ -0,0 +1,212 @@
+package nlp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+)
+
+// Tagger file format, all numbers are unsigned varints unless noted:
+//
+//	magic    "NLPT"
+//	version  1
+//	tags     count, then per tag (sorted): length, tag
+//	words    count, then per unambiguous word (sorted): length, word, tag number
+//	features count, then per feature (sorted):
+//	           shared prefix length with previous feature, suffix length, suffix
+//	           number of weights, then per weight:
+//	             tag number delta, weight (float32, 4 bytes little endian)
+//	checksum CRC32 (IEEE) of all the above, 4 bytes little endian
+//
+// Tag numbers are indices in the list of tags, zero weights are not stored.
+
+const (
+	taggerMagic   = "NLPT"
+	taggerVersion = 1
+)
+
+// ErrBadTagger is returned when reading a corrupt or unsupported tagger file.
+var ErrBadTagger = errors.New("bad tagger file")
+
+// WriteTo writes the tagger to w. It implements io.WriterTo.
+func (tg *Tagger) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(tg.encode())
+	return int64(n), err
+}
+
+func (tg *Tagger) encode() []byte {
+	var buf []byte
+	putUvarint := func(v int) {
+		buf = binary.AppendUvarint(buf, uint64(v))
+	}
+	putString := func(s string) {
+		putUvarint(len(s))
+		buf = append(buf, s...)
+	}
+
+	buf = append(buf, taggerMagic...)
+	putUvarint(taggerVersion)
+
+	putUvarint(len(tg.tags))
+	for _, tag := range tg.tags {
+		putString(tag)
+	}
+
+	words := make([]string, 0, len(tg.words))
+	for w := range tg.words {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	putUvarint(len(words))
+	for _, w := range words {
+		putString(w)
+		putUvarint(tg.words[w])
+	}
+
+	features := make([]string, 0, len(tg.weights))
+	for f := range tg.weights {
+		features = append(features, f)
+	}
+	sort.Strings(features)
+	putUvarint(len(features))
+	prev := ""
+	for _, f := range features {
+		shared := commonPrefixLen(prev, f)
+		putUvarint(shared)
+		putString(f[shared:])
+		prev = f
+
+		weights := tg.weights[f]
+		nonZero := 0
+		for _, w := range weights {
+			if w != 0 {
+				nonZero++
+			}
+		}
+		putUvarint(nonZero)
+		prevTag := 0
+		for tag, w := range weights {
+			if w == 0 {
+				continue
+			}
+			putUvarint(tag - prevTag)
+			prevTag = tag
+			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(w)))
+		}
+	}
+
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+}
+
+// ReadFrom replaces the tagger with a tagger read from r. It implements
+// io.ReaderFrom. Corrupt data returns an error wrapping ErrBadTagger.
+func (tg *Tagger) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	if err := tg.decode(data); err != nil {
+		return int64(len(data)), fmt.Errorf("%w: %s", ErrBadTagger, err)
+	}
+	return int64(len(data)), nil
+}
+
+func (tg *Tagger) decode(data []byte) error {
+	const sumSize = 4
+	if len(data) < len(taggerMagic)+sumSize {
+		return fmt.Errorf("file too short")
+	}
+	body := data[:len(data)-sumSize]
+	if sum := binary.LittleEndian.Uint32(data[len(body):]); sum != crc32.ChecksumIEEE(body) {
+		return fmt.Errorf("checksum mismatch")
+	}
+	if !bytes.HasPrefix(body, []byte(taggerMagic)) {
+		return fmt.Errorf("bad magic")
+	}
+
+	d := decoder{data: body[len(taggerMagic):]}
+	if version := d.uvarint(); d.err == nil && version != taggerVersion {
+		return fmt.Errorf("unsupported version %d", version)
+	}
+
+	numTags := d.count()
+	tags := make([]string, 0, numTags)
+	for i := 0; i < numTags && d.err == nil; i++ {
+		tag := string(d.bytes(d.count()))
+		if i > 0 && tag <= tags[i-1] {
+			d.fail("tags not sorted")
+		}
+		tags = append(tags, tag)
+	}
+	if d.err == nil && numTags == 0 {
+		d.fail("no tags")
+	}
+
+	numWords := d.count()
+	words := make(map[string]int, numWords)
+	prev := ""
+	for i := 0; i < numWords && d.err == nil; i++ {
+		w := string(d.bytes(d.count()))
+		if i > 0 && w <= prev {
+			d.fail("words not sorted")
+		}
+		prev = w
+		words[w] = d.int(numTags - 1)
+	}
+
+	numFeatures := d.count()
+	weights := make(map[string][]float64, numFeatures)
+	prev = ""
+	for i := 0; i < numFeatures && d.err == nil; i++ {
+		shared := d.int(len(prev))
+		f := prev[:shared] + string(d.bytes(d.count()))
+		if i > 0 && f <= prev {
+			d.fail("features not sorted")
+		}
+		prev = f
+
+		numWeights := d.int(numTags)
+		if numWeights == 0 {
+			d.fail("no weights")
+		}
+		w := make([]float64, numTags)
+		tag := 0
+		for j := 0; j < numWeights && d.err == nil; j++ {
+			delta := d.int(numTags - 1)
+			if j > 0 && delta == 0 {
+				d.fail("duplicate tag")
+			}
+			tag += delta
+			if tag >= numTags {
+				d.fail("bad tag number")
+				break
+			}
+			b := d.bytes(4)
+			if d.err != nil {
+				break
+			}
+			v := math.Float32frombits(binary.LittleEndian.Uint32(b))
+			if v == 0 || math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+				d.fail("bad weight")
+			}
+			w[tag] = float64(v)
+		}
+		weights[f] = w
+	}
+
+	if d.err == nil && len(d.data) > 0 {
+		d.fail("trailing data")
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	tg.tags, tg.words, tg.weights = tags, words, weights
+	return nil
+}
@@ -0,0 +1,108 @@
+package nlp
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func loadTaggedSentences(t *testing.T, fileName string) []TaggedSentence {
+	file, err := os.Open(fileName)
+	require.NoError(t, err)
+	defer file.Close()
+
+	sentences, err := ReadTaggedSentences(file)
+	require.NoError(t, err)
+	return sentences
+}
+
+func TestEnglishTaggerAccuracy(t *testing.T) {
+	tg := EnglishTagger()
+	total, correct := 0, 0
+	for _, sent := range loadTaggedSentences(t, "testdata/tagged.txt") {
+		tags := tg.TagWords(sent.Words)
+		for i, tag := range tags {
+			total++
+			if tag == sent.Tags[i] {
+				correct++
+			} else {
+				t.Logf("%s: expected %s, got %s", sent.Words[i], sent.Tags[i], tag)
+			}
+		}
+	}
+	accuracy := float64(correct) / float64(total)
+	t.Logf("accuracy: %.3f (%d/%d)", accuracy, correct, total)
+	require.GreaterOrEqual(t, accuracy, 0.9)
+}
+
+// The embedded model must be the result of training on the embedded corpus
+// (run "go generate" after changing the corpus or the training).
+func TestEnglishTaggerUpToDate(t *testing.T) {
+	tg, err := TrainTagger(loadTaggedSentences(t, "taggerdata/en.txt"), 10)
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(englishModel, tg.encode()), "embedded model is out of date")
+}
+
+func TestTaggerTokens(t *testing.T) {
+	tok, err := NewTokenizer(WithContractions())
+	require.NoError(t, err)
+	tokens := tok.TokenizeWithOffsets("The detective didn't open the door.")
+
+	tags := EnglishTagger().Tag(tokens)
+	require.Equal(t, []string{"DET", "NOUN", "AUX", "PART", "VERB", "DET", "NOUN"}, tags)
+}
+
+func TestTaggerZero(t *testing.T) {
+	var tg Tagger
+	require.Empty(t, tg.Tags())
+	require.Equal(t, []string{"", ""}, tg.TagWords([]string{"Hello", "world"}))
+	require.Empty(t, tg.TagWords(nil))
+}
+
+func TestTrainTagger(t *testing.T) {
+	sentences := []TaggedSentence{
+		{Words: []string{"dogs", "bark"}, Tags: []string{"N", "V"}},
+		{Words: []string{"cats", "sleep"}, Tags: []string{"N", "V"}},
+	}
+	tg, err := TrainTagger(sentences, 5)
+	require.NoError(t, err)
+	require.Equal(t, []string{"N", "V"}, tg.Tags())
+	require.Equal(t, []string{"N", "V"}, tg.TagWords([]string{"dogs", "sleep"}))
+
+	var buf bytes.Buffer
+	_, err = tg.WriteTo(&buf)
+	require.NoError(t, err)
+	var tg2 Tagger
+	_, err = tg2.ReadFrom(&buf)
+	require.NoError(t, err)
+	require.Equal(t, tg, &tg2)
+
+	_, err = TrainTagger([]TaggedSentence{{Words: []string{"a"}}}, 5)
+	require.Error(t, err)
+	_, err = TrainTagger(nil, 5)
+	require.Error(t, err)
+}
+
+func TestReadTaggedSentencesBad(t *testing.T) {
+	for _, text := range []string{"dog/N bark", "dog/", "/N"} {
+		_, err := ReadTaggedSentences(bytes.NewBufferString(text))
+		require.Error(t, err, text)
+	}
+}
+
+func TestTaggerReadCorrupt(t *testing.T) {
+	data := englishModel
+	for _, size := range []int{0, 3, len(data) / 2, len(data) - 1} {
+		var tg Tagger
+		_, err := tg.ReadFrom(bytes.NewReader(data[:size]))
+		require.ErrorIs(t, err, ErrBadTagger, "size %d", size)
+	}
+
+	bad := append([]byte(nil), data...)
+	bad[len(bad)/2] ^= 0xff
+	var tg Tagger
+	_, err := tg.ReadFrom(bytes.NewReader(bad))
+	require.ErrorIs(t, err, ErrBadTagger)
+}
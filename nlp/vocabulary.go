@@ -0,0 +1,161 @@
+package nlp
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/osshu320/nlp/stemmer"
+)
+
+// Vocabulary is a set of words with their frequency in a corpus, used for
+// spelling suggestions. It is safe for concurrent use.
+type Vocabulary struct {
+	tokenizer *Tokenizer
+
+	mu     sync.RWMutex
+	counts map[string]int
+	tree   *bkNode
+}
+
+// Suggestion is a spelling suggestion.
+type Suggestion struct {
+	Word     string
+	Distance int // Damerau-Levenshtein distance to the looked up word
+	Count    int // Number of occurrences in the vocabulary
+}
+
+// bkNode is a node in a BK-tree, every word in the subtree children[d] is at
+// distance d from word.
+type bkNode struct {
+	word     string
+	children map[int]*bkNode
+}
+
+// NewVocabulary returns an empty Vocabulary that uses t to tokenize text and
+// looked up words. If t is nil, words are case folded but not stemmed.
+func NewVocabulary(t *Tokenizer) *Vocabulary {
+	if t == nil {
+		t = &Tokenizer{stemmer: stemmer.Func(func(word string) string { return word })}
+	}
+	return &Vocabulary{
+		tokenizer: t,
+		counts:    make(map[string]int),
+	}
+}
+
+// Add adds the tokens of text to the vocabulary.
+func (v *Vocabulary) Add(text string) {
+	tokens := v.tokenizer.Tokenize(text)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, tok := range tokens {
+		if v.counts[tok] == 0 {
+			v.insert(tok)
+		}
+		v.counts[tok]++
+	}
+}
+
+func (v *Vocabulary) insert(word string) {
+	if v.tree == nil {
+		v.tree = &bkNode{word: word}
+		return
+	}
+
+	node := v.tree
+	for {
+		d := DamerauLevenshtein(word, node.word)
+		child, ok := node.children[d]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[d] = &bkNode{word: word}
+			return
+		}
+		node = child
+	}
+}
+
+// Len returns the number of distinct words in the vocabulary.
+func (v *Vocabulary) Len() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return len(v.counts)
+}
+
+// Count returns the number of occurrences of word in the vocabulary.
+func (v *Vocabulary) Count(word string) int {
+	norm, ok := v.norm(word)
+	if !ok {
+		return 0
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.counts[norm]
+}
+
+// norm returns the normalized form of word, it returns false if word is not
+// a single token (e.g. a stop word).
+func (v *Vocabulary) norm(word string) (string, bool) {
+	tokens := v.tokenizer.Tokenize(word)
+	if len(tokens) != 1 {
+		return "", false
+	}
+	return tokens[0], true
+}
+
+// Suggest returns up to k words of the vocabulary that are at most
+// maxDistance edits away from word. Closer words come first, words at the
+// same distance are ordered by frequency, most frequent first. If word is in
+// the vocabulary, it is the first suggestion (with Distance 0).
+func (v *Vocabulary) Suggest(word string, maxDistance, k int) []Suggestion {
+	norm, ok := v.norm(word)
+	if !ok || k <= 0 {
+		return nil
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var suggestions []Suggestion
+	stack := []*bkNode{}
+	if v.tree != nil {
+		stack = append(stack, v.tree)
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := DamerauLevenshtein(norm, node.word)
+		if d <= maxDistance {
+			suggestions = append(suggestions, Suggestion{node.word, d, v.counts[node.word]})
+		}
+		// triangle inequality: matches are in children at distance d±maxDistance
+		for cd, child := range node.children {
+			if cd >= d-maxDistance && cd <= d+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Word < b.Word
+	})
+	if len(suggestions) > k {
+		suggestions = suggestions[:k]
+	}
+	return suggestions
+}
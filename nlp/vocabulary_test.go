@@ -0,0 +1,108 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var distanceCases = []struct {
+	a, b        string
+	levenshtein int
+	damerau     int
+}{
+	{"", "", 0, 0},
+	{"", "abc", 3, 3},
+	{"kitten", "sitting", 3, 3},
+	{"holmes", "holmes", 0, 0},
+	{"teh", "the", 2, 1},
+	{"ca", "abc", 3, 2},
+	{"watson", "wtason", 2, 1},
+	{"café", "cafe", 1, 1},
+	{"日本語", "日本", 1, 1},
+}
+
+func TestDistance(t *testing.T) {
+	for _, tc := range distanceCases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			require.Equal(t, tc.levenshtein, Levenshtein(tc.a, tc.b), "levenshtein")
+			require.Equal(t, tc.levenshtein, Levenshtein(tc.b, tc.a), "levenshtein reversed")
+			require.Equal(t, tc.damerau, DamerauLevenshtein(tc.a, tc.b), "damerau")
+			require.Equal(t, tc.damerau, DamerauLevenshtein(tc.b, tc.a), "damerau reversed")
+		})
+	}
+}
+
+func newTestVocabulary() *Vocabulary {
+	v := NewVocabulary(nil)
+	for _, text := range indexDocs {
+		v.Add(text)
+	}
+	return v
+}
+
+func suggestionWords(suggestions []Suggestion) []string {
+	var words []string
+	for _, s := range suggestions {
+		words = append(words, s.Word)
+	}
+	return words
+}
+
+var suggestCases = []struct {
+	word        string
+	maxDistance int
+	k           int
+	words       []string
+}{
+	{"holmes", 0, 5, []string{"holmes"}},
+	{"Hlomes", 1, 5, []string{"holmes"}},
+	{"holms", 1, 5, []string{"holmes"}},
+	{"wattson", 1, 5, []string{"watson"}},
+	{"listend", 1, 5, []string{"listened"}},
+	{"moriarty", 2, 5, nil},
+	{"", 2, 5, nil},
+}
+
+func TestVocabularySuggest(t *testing.T) {
+	v := newTestVocabulary()
+	for _, tc := range suggestCases {
+		t.Run(tc.word, func(t *testing.T) {
+			suggestions := v.Suggest(tc.word, tc.maxDistance, tc.k)
+			require.Equal(t, tc.words, suggestionWords(suggestions))
+		})
+	}
+}
+
+func TestVocabularyFrequency(t *testing.T) {
+	v := NewVocabulary(nil)
+	v.Add("The then the them. The then.")
+	require.Equal(t, 3, v.Len())
+	require.Equal(t, 3, v.Count("THE"))
+
+	suggestions := v.Suggest("thn", 2, 5)
+	expected := []Suggestion{
+		{"the", 1, 3},
+		{"then", 1, 2},
+		{"them", 2, 1},
+	}
+	require.Equal(t, expected, suggestions)
+	require.Equal(t, expected[:2], v.Suggest("thn", 2, 2))
+}
+
+func TestVocabularySuggestAll(t *testing.T) {
+	v := newTestVocabulary()
+	for _, word := range []string{"sherlock", "holme", "vallye", "a", "redheaded", "xyz"} {
+		for maxDistance := 0; maxDistance <= 3; maxDistance++ {
+			// BK-tree lookup must find the same words as a linear scan
+			var expected []string
+			for w := range v.counts {
+				if DamerauLevenshtein(word, w) <= maxDistance {
+					expected = append(expected, w)
+				}
+			}
+			words := suggestionWords(v.Suggest(word, maxDistance, len(v.counts)))
+			require.ElementsMatch(t, expected, words, "%s/%d", word, maxDistance)
+		}
+	}
+}